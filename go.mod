@@ -2,6 +2,7 @@ module github.com/vmware/wavefront-adapter-for-istio
 
 require (
 	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/ghodss/yaml v1.0.0
 	github.com/gogo/googleapis v0.0.0-20180223154316-0cd9801be74a // indirect
 	github.com/gogo/protobuf v1.1.1
 	github.com/hashicorp/errwrap v0.0.0-20180715044906-d6c0cd880357 // indirect
@@ -11,8 +12,8 @@ require (
 	github.com/natefinch/lumberjack v2.0.0+incompatible // indirect
 	github.com/pkg/errors v0.8.1 // indirect
 	github.com/rcrowley/go-metrics v0.0.0-20190706150252-9beb055b7962
-	github.com/spf13/cobra v0.0.0-20180821161202-6fd8e29b07d8 // indirect
-	github.com/spf13/pflag v1.0.1 // indirect
+	github.com/spf13/cobra v0.0.0-20180821161202-6fd8e29b07d8
+	github.com/spf13/pflag v1.0.1
 	github.com/wavefronthq/go-metrics-wavefront v1.0.2
 	github.com/wavefronthq/wavefront-sdk-go v0.9.5
 	go.uber.org/atomic v1.3.2 // indirect
@@ -16,27 +16,260 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // registers the profiling endpoints served at the debug port
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/vmware/wavefront-adapter-for-istio/wavefront"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	"istio.io/istio/pkg/log"
 )
 
-func main() {
-	addr := ""
-	if len(os.Args) > 1 {
-		addr = os.Args[1]
+// envPrefix is the prefix of the environment variables overriding the flags.
+const envPrefix = "WAVEFRONT_ADAPTER_"
+
+// version is the adapter version, set at build time.
+var version = "dev"
+
+// args holds the command-line arguments of the adapter.
+type args struct {
+	address           string
+	iface             string
+	port              string
+	logLevel          string
+	tlsCert           string
+	tlsKey            string
+	tlsCA             string
+	requireClientCert bool
+	clientSANs        []string
+	healthPort        int
+	metricsPort       int
+	debugPort         int
+	configFile        string
+	shutdownTimeout   time.Duration
+}
+
+// envName returns the environment variable overriding the given flag.
+func envName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(flag, "-", "_", -1))
+}
+
+// applyEnv sets the flags that weren't given on the command line from their
+// environment variables, if any.
+func applyEnv(flags *pflag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+		value, found := os.LookupEnv(envName(f.Name))
+		if err != nil || f.Changed || !found {
+			return
+		}
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %v", value, envName(f.Name), setErr)
+		}
+	})
+	return err
+}
+
+// interfaceAddress returns the IP address of the given network interface,
+// preferring IPv4 addresses.
+func interfaceAddress(name string) (string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	var found net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+		if found == nil {
+			found = ipNet.IP
+		}
+	}
+	if found == nil {
+		return "", fmt.Errorf("interface %s has no IP address", name)
+	}
+	return found.String(), nil
+}
+
+// listenAddress returns the address the adapter listens at.
+func (a *args) listenAddress() (string, error) {
+	host := a.address
+	if a.iface != "" {
+		if host != "" {
+			return "", errors.New("only one of --address and --interface may be given")
+		}
+		var err error
+		if host, err = interfaceAddress(a.iface); err != nil {
+			return "", fmt.Errorf("couldn't find the address of interface %s: %v", a.iface, err)
+		}
+	}
+	return net.JoinHostPort(host, a.port), nil
+}
+
+// options returns the adapter options for the arguments.
+func (a *args) options() ([]wavefront.Option, error) {
+	var options []wavefront.Option
+	if a.tlsCert != "" || a.tlsKey != "" || a.tlsCA != "" {
+		options = append(options, wavefront.WithTLS(wavefront.TLSOptions{
+			CertFile:          a.tlsCert,
+			KeyFile:           a.tlsKey,
+			CAFile:            a.tlsCA,
+			RequireClientCert: a.requireClientCert,
+			ClientSANs:        a.clientSANs,
+		}))
+	}
+	if a.healthPort != 0 {
+		options = append(options, wavefront.WithHealthAddress(fmt.Sprintf(":%d", a.healthPort)))
+	}
+	if a.metricsPort != 0 {
+		options = append(options, wavefront.WithMetricsAddress(fmt.Sprintf(":%d", a.metricsPort)))
+	}
+	if a.configFile != "" {
+		cfg, err := config.Load(a.configFile)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, wavefront.WithConfig(cfg))
+	}
+	return options, nil
+}
+
+// logLevels maps the accepted log level names to their levels.
+var logLevels = map[string]log.Level{
+	"debug": log.DebugLevel,
+	"info":  log.InfoLevel,
+	"warn":  log.WarnLevel,
+	"error": log.ErrorLevel,
+	"none":  log.NoneLevel,
+}
+
+// configureLogging sets the output level of the default logging scope.
+func configureLogging(name string) error {
+	level, found := logLevels[strings.ToLower(name)]
+	if !found {
+		return fmt.Errorf("invalid log level %q", name)
 	}
+	options := log.DefaultOptions()
+	options.SetOutputLevel(log.DefaultScopeName, level)
+	return log.Configure(options)
+}
 
-	s, err := wavefront.NewWavefrontAdapter(addr)
+// run starts the adapter and serves until it stops.
+func run(a *args) error {
+	if err := configureLogging(a.logLevel); err != nil {
+		return err
+	}
+
+	addr, err := a.listenAddress()
 	if err != nil {
-		fmt.Printf("unable to start server: %v", err)
-		os.Exit(-1)
+		return err
+	}
+	options, err := a.options()
+	if err != nil {
+		return err
+	}
+
+	if a.debugPort != 0 {
+		go func() {
+			if err := http.ListenAndServe(fmt.Sprintf(":%d", a.debugPort), nil); err != nil {
+				log.Errorf("debug server stopped: %v", err)
+			}
+		}()
+	}
+
+	s, err := wavefront.NewWavefrontAdapter(addr, options...)
+	if err != nil {
+		return fmt.Errorf("unable to start server: %v", err)
 	}
 
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+
 	shutdown := make(chan error, 1)
 	go func() {
 		s.Run(shutdown)
 	}()
-	_ = <-shutdown
+
+	select {
+	case err := <-shutdown:
+		return err
+	case sig := <-signals:
+		log.Infof("received %v, shutting down within %v", sig, a.shutdownTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			return fmt.Errorf("unable to shut down gracefully: %v", err)
+		}
+		return nil
+	}
+}
+
+// newCommand creates the adapter command.
+func newCommand() *cobra.Command {
+	a := &args{}
+	cmd := &cobra.Command{
+		Use:   "wavefront [port]",
+		Short: "Wavefront by VMware adapter for Istio",
+		Long: "Wavefront by VMware adapter for Istio.\n\n" +
+			"Every flag may also be set with an environment variable named after it,\n" +
+			"e.g. " + envName("log-level") + " for --log-level. Flags take precedence.",
+		Version:       version,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, positional []string) error {
+			if err := applyEnv(cmd.Flags()); err != nil {
+				return err
+			}
+			// the port used to be the only, positional, argument
+			if len(positional) == 1 && !cmd.Flags().Changed("port") {
+				a.port = positional[0]
+			}
+			return run(a)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&a.address, "address", "", "IP address or host name to listen at; all interfaces if empty")
+	flags.StringVar(&a.iface, "interface", "", "network interface to listen at, instead of --address")
+	flags.StringVar(&a.port, "port", "8000", "port to listen at for Mixer requests")
+	flags.StringVar(&a.logLevel, "log-level", "info", "log level, one of debug, info, warn, error or none")
+	flags.StringVar(&a.tlsCert, "tls-cert", "", "server certificate file in PEM format, enables TLS")
+	flags.StringVar(&a.tlsKey, "tls-key", "", "server private key file in PEM format")
+	flags.StringVar(&a.tlsCA, "tls-ca", "", "CA certificates file in PEM format to verify client certificates with")
+	flags.BoolVar(&a.requireClientCert, "tls-require-client-cert", false, "require clients to present a certificate signed by --tls-ca")
+	flags.StringSliceVar(&a.clientSANs, "tls-client-san", nil, "accepted client certificate URI or DNS subject alternative names, requires --tls-require-client-cert; any if empty")
+	flags.IntVar(&a.healthPort, "health-port", 0, "port to serve the HTTP health endpoints at; disabled if 0")
+	flags.IntVar(&a.metricsPort, "metrics-port", 0, "port to serve the adapter metrics at in the Prometheus format; disabled if 0")
+	flags.IntVar(&a.debugPort, "debug-port", 0, "port to serve the profiling endpoints at; disabled if 0")
+	flags.StringVar(&a.configFile, "config", "", "adapter params YAML file used for requests without a handler configuration")
+	flags.DurationVar(&a.shutdownTimeout, "shutdown-timeout", 20*time.Second, "time to complete the requests in flight and flush the metrics on shutdown")
+	return cmd
+}
+
+func main() {
+	if err := newCommand().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(-1)
+	}
 }
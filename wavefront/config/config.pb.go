@@ -29,6 +29,7 @@ import strconv "strconv"
 
 import strings "strings"
 import reflect "reflect"
+import sortkeys "github.com/gogo/protobuf/sortkeys"
 
 import binary "encoding/binary"
 import types "github.com/gogo/protobuf/types"
@@ -47,20 +48,89 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+// Describes how typed dimension values are formatted as tag values.
+type Params_TagFormat int32
+
+const (
+	// Formats IP addresses in dotted notation for IPv4 and colon notation for
+	// IPv6, timestamps in RFC 3339 format in UTC, and durations as Go duration
+	// strings, e.g. 1m30s.
+	AUTO Params_TagFormat = 0
+	// Formats timestamps as seconds since the epoch.
+	EPOCH_SECONDS Params_TagFormat = 1
+	// Formats timestamps as milliseconds since the epoch.
+	EPOCH_MILLISECONDS Params_TagFormat = 2
+	// Formats IP addresses in colon notation, including IPv4 ones, e.g.
+	// ::ffff:a00:1.
+	COLON_NOTATION Params_TagFormat = 3
+)
+
+var Params_TagFormat_name = map[int32]string{
+	0: "AUTO",
+	1: "EPOCH_SECONDS",
+	2: "EPOCH_MILLISECONDS",
+	3: "COLON_NOTATION",
+}
+var Params_TagFormat_value = map[string]int32{
+	"AUTO":               0,
+	"EPOCH_SECONDS":      1,
+	"EPOCH_MILLISECONDS": 2,
+	"COLON_NOTATION":     3,
+}
+
+func (Params_TagFormat) EnumDescriptor() ([]byte, []int) { return fileDescriptorConfig, []int{0, 0} }
+
+// How the metrics are spread over the proxies.
+type Params_WavefrontProxy_LoadBalancing int32
+
+const (
+	// Each point goes to the next proxy in turn.
+	ROUND_ROBIN Params_WavefrontProxy_LoadBalancing = 0
+	// Each series always goes to the same proxy, as long as it's healthy,
+	// which is chosen by hashing the metric name, source and tags.
+	CONSISTENT_HASH Params_WavefrontProxy_LoadBalancing = 1
+)
+
+var Params_WavefrontProxy_LoadBalancing_name = map[int32]string{
+	0: "ROUND_ROBIN",
+	1: "CONSISTENT_HASH",
+}
+var Params_WavefrontProxy_LoadBalancing_value = map[string]int32{
+	"ROUND_ROBIN":     0,
+	"CONSISTENT_HASH": 1,
+}
+
+func (Params_WavefrontProxy_LoadBalancing) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{0, 1, 0}
+}
+
 // Describes metric types as in [Wavefront](https://docs.wavefront.com/metric_types.html).
 type Params_MetricInfo_Type int32
 
 const (
 	// Reserved for unknown metric types.
 	UNKNOWN Params_MetricInfo_Type = 0
-	// Represents a gauge metric type.
+	// Represents a gauge metric type, which reports the last value by
+	// default.
 	GAUGE Params_MetricInfo_Type = 1
-	// Represents a counter metric type.
+	// Represents a counter metric type, which reports the running total of
+	// the values as `<name>.count`. The values must be integers: the others
+	// are dropped.
 	COUNTER Params_MetricInfo_Type = 2
-	// Represents a delta counter metric type.
+	// Represents a delta counter metric type. The values must be integers:
+	// the others are dropped.
 	DELTA_COUNTER Params_MetricInfo_Type = 3
 	// Represents a histogram metric type.
 	HISTOGRAM Params_MetricInfo_Type = 4
+	// Represents a [Wavefront histogram](https://docs.wavefront.com/proxies_histograms.html)
+	// metric type, which is sent as a distribution.
+	WAVEFRONT_HISTOGRAM Params_MetricInfo_Type = 5
+	// Represents a timer metric type, which reports the count, the 1, 5 and
+	// 15-minute rates and the percentiles of the values.
+	TIMER Params_MetricInfo_Type = 6
+	// Represents a meter metric type, which reports the count and the 1, 5
+	// and 15-minute rates of the values.
+	METER Params_MetricInfo_Type = 7
 )
 
 var Params_MetricInfo_Type_name = map[int32]string{
@@ -69,19 +139,142 @@ var Params_MetricInfo_Type_name = map[int32]string{
 	2: "COUNTER",
 	3: "DELTA_COUNTER",
 	4: "HISTOGRAM",
+	5: "WAVEFRONT_HISTOGRAM",
+	6: "TIMER",
+	7: "METER",
 }
 var Params_MetricInfo_Type_value = map[string]int32{
-	"UNKNOWN":       0,
-	"GAUGE":         1,
-	"COUNTER":       2,
-	"DELTA_COUNTER": 3,
-	"HISTOGRAM":     4,
+	"UNKNOWN":             0,
+	"GAUGE":               1,
+	"COUNTER":             2,
+	"DELTA_COUNTER":       3,
+	"HISTOGRAM":           4,
+	"WAVEFRONT_HISTOGRAM": 5,
+	"TIMER":               6,
+	"METER":               7,
 }
 
 func (Params_MetricInfo_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptorConfig, []int{0, 2, 0}
 }
 
+// Describes the granularities of a Wavefront histogram.
+type Params_MetricInfo_Granularity int32
+
+const (
+	// Represents distributions aggregated by the minute, the default.
+	MINUTE Params_MetricInfo_Granularity = 0
+	// Represents distributions aggregated by the hour.
+	HOUR Params_MetricInfo_Granularity = 1
+	// Represents distributions aggregated by the day.
+	DAY Params_MetricInfo_Granularity = 2
+)
+
+var Params_MetricInfo_Granularity_name = map[int32]string{
+	0: "MINUTE",
+	1: "HOUR",
+	2: "DAY",
+}
+var Params_MetricInfo_Granularity_value = map[string]int32{
+	"MINUTE": 0,
+	"HOUR":   1,
+	"DAY":    2,
+}
+
+func (Params_MetricInfo_Granularity) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{0, 2, 1}
+}
+
+// Describes the units a duration value could be reported in.
+type Params_MetricInfo_DurationUnit int32
+
+const (
+	// Represents nanoseconds, the default unit.
+	NANOSECONDS Params_MetricInfo_DurationUnit = 0
+	// Represents microseconds.
+	MICROSECONDS Params_MetricInfo_DurationUnit = 1
+	// Represents milliseconds.
+	MILLISECONDS Params_MetricInfo_DurationUnit = 2
+	// Represents seconds, for gauges only.
+	SECONDS Params_MetricInfo_DurationUnit = 3
+)
+
+var Params_MetricInfo_DurationUnit_name = map[int32]string{
+	0: "NANOSECONDS",
+	1: "MICROSECONDS",
+	2: "MILLISECONDS",
+	3: "SECONDS",
+}
+var Params_MetricInfo_DurationUnit_value = map[string]int32{
+	"NANOSECONDS":  0,
+	"MICROSECONDS": 1,
+	"MILLISECONDS": 2,
+	"SECONDS":      3,
+}
+
+func (Params_MetricInfo_DurationUnit) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{0, 2, 2}
+}
+
+// Describes how the instances of series over the limit are handled.
+type Params_MetricInfo_Overflow int32
+
+const (
+	// Drops the instances, the default.
+	DROP Params_MetricInfo_Overflow = 0
+	// Collapses the instances into a single series of the metric, whose tag
+	// values are all `__overflow__`.
+	COLLAPSE Params_MetricInfo_Overflow = 1
+)
+
+var Params_MetricInfo_Overflow_name = map[int32]string{
+	0: "DROP",
+	1: "COLLAPSE",
+}
+var Params_MetricInfo_Overflow_value = map[string]int32{
+	"DROP":     0,
+	"COLLAPSE": 1,
+}
+
+func (Params_MetricInfo_Overflow) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{0, 2, 3}
+}
+
+// Describes how gauge values are aggregated over a flush interval.
+type Params_MetricInfo_Aggregation int32
+
+const (
+	// Reports the last value, the default.
+	LAST Params_MetricInfo_Aggregation = 0
+	// Reports the maximum value.
+	MAX Params_MetricInfo_Aggregation = 1
+	// Reports the minimum value.
+	MIN Params_MetricInfo_Aggregation = 2
+	// Reports the sum of the values, or 0 if there were none.
+	SUM Params_MetricInfo_Aggregation = 3
+	// Reports the mean of the values.
+	MEAN Params_MetricInfo_Aggregation = 4
+)
+
+var Params_MetricInfo_Aggregation_name = map[int32]string{
+	0: "LAST",
+	1: "MAX",
+	2: "MIN",
+	3: "SUM",
+	4: "MEAN",
+}
+var Params_MetricInfo_Aggregation_value = map[string]int32{
+	"LAST": 0,
+	"MAX":  1,
+	"MIN":  2,
+	"SUM":  3,
+	"MEAN": 4,
+}
+
+func (Params_MetricInfo_Aggregation) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{0, 2, 4}
+}
+
 // Configuration format for the `wavefront` adapter.
 type Params struct {
 	// One of the Wavefront service credentials must be supplied.
@@ -90,7 +283,7 @@ type Params struct {
 	//	*Params_Direct
 	//	*Params_Proxy
 	Credentials isParams_Credentials `protobuf_oneof:"credentials"`
-	// The metrics flush interval.
+	// The metrics flush interval. It must be at least 1s, and defaults to 5s.
 	FlushInterval time.Duration `protobuf:"bytes,3,opt,name=flush_interval,json=flushInterval,stdduration" json:"flush_interval"`
 	// The source tag for all metrics handled by this adapter.
 	Source string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
@@ -100,6 +293,33 @@ type Params struct {
 	Metrics []*Params_MetricInfo `protobuf:"bytes,6,rep,name=metrics" json:"metrics,omitempty"`
 	// The log configuration.
 	Logs *Params_Logs `protobuf:"bytes,7,opt,name=logs" json:"logs,omitempty"`
+	// The prefix of the metrics about the adapter itself, such as the number
+	// of requests, dropped instances and sender failures. Defaults to adapter.
+	AdapterMetricsPrefix string `protobuf:"bytes,8,opt,name=adapter_metrics_prefix,json=adapterMetricsPrefix,proto3" json:"adapter_metrics_prefix,omitempty"`
+	// The maximum number of active series across all the metrics of the handler.
+	// There is no limit if 0. The handlers sending to the same place with the
+	// same limit, whose metrics share a reporter, share the limit as well.
+	MaxSeries int32 `protobuf:"varint,9,opt,name=max_series,json=maxSeries,proto3" json:"max_series,omitempty"`
+	// The default tag rules of the metrics.
+	Tags *Params_TagRules `protobuf:"bytes,10,opt,name=tags" json:"tags,omitempty"`
+	// The rewrites of the dimension values, applied in order before the tag
+	// rules.
+	TagRewrites []*Params_TagRewrite `protobuf:"bytes,11,rep,name=tag_rewrites,json=tagRewrites" json:"tag_rewrites,omitempty"`
+	// The formats of the typed dimension values, by dimension. The formats
+	// that don't apply to the type of a value are ignored.
+	// Ex: request_time: EPOCH_SECONDS
+	TagFormats map[string]Params_TagFormat `protobuf:"bytes,12,rep,name=tag_formats,json=tagFormats" json:"tag_formats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=wavefront.config.Params_TagFormat"`
+	// The number of flush intervals after which the series that weren't
+	// updated stop being reported, and are forgotten. Wavefront histograms are
+	// kept at least until their pending distributions are reported, and counters
+	// start over from 0 if updated again. Series never expire if 0.
+	SeriesExpiryIntervals int32 `protobuf:"varint,13,opt,name=series_expiry_intervals,json=seriesExpiryIntervals,proto3" json:"series_expiry_intervals,omitempty"`
+	// The spool of the points that can't be sent during an outage, e.g. because
+	// the buffer of the direct sender is full or the proxy is unreachable. The
+	// spooled points are replayed oldest first once the sender recovers. The
+	// points of a batch the direct sender fails to flush aren't spooled: they go
+	// back to its buffer, and are dropped if it's full by then.
+	Spool *Params_Spool `protobuf:"bytes,14,opt,name=spool" json:"spool,omitempty"`
 }
 
 func (m *Params) Reset()                    { *m = Params{} }
@@ -179,6 +399,55 @@ func (m *Params) GetLogs() *Params_Logs {
 	return nil
 }
 
+func (m *Params) GetAdapterMetricsPrefix() string {
+	if m != nil {
+		return m.AdapterMetricsPrefix
+	}
+	return ""
+}
+
+func (m *Params) GetMaxSeries() int32 {
+	if m != nil {
+		return m.MaxSeries
+	}
+	return 0
+}
+
+func (m *Params) GetTags() *Params_TagRules {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *Params) GetTagRewrites() []*Params_TagRewrite {
+	if m != nil {
+		return m.TagRewrites
+	}
+	return nil
+}
+
+func (m *Params) GetTagFormats() map[string]Params_TagFormat {
+	if m != nil {
+		return m.TagFormats
+	}
+	return nil
+}
+
+func (m *Params) GetSeriesExpiryIntervals() int32 {
+	if m != nil {
+		return m.SeriesExpiryIntervals
+	}
+	return 0
+}
+
+func (m *Params) GetSpool() *Params_Spool {
+	if m != nil {
+		return m.Spool
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Params) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Params_OneofMarshaler, _Params_OneofUnmarshaler, _Params_OneofSizer, []interface{}{
@@ -259,6 +528,9 @@ type Params_WavefrontDirect struct {
 	Server string `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
 	// The Wavefront API token.
 	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// The maximum number of points kept in memory while they can't be sent,
+	// e.g. during an outage. Defaults to 50000.
+	MaxBufferSize int32 `protobuf:"varint,3,opt,name=max_buffer_size,json=maxBufferSize,proto3" json:"max_buffer_size,omitempty"`
 }
 
 func (m *Params_WavefrontDirect) Reset()                    { *m = Params_WavefrontDirect{} }
@@ -279,10 +551,40 @@ func (m *Params_WavefrontDirect) GetToken() string {
 	return ""
 }
 
+func (m *Params_WavefrontDirect) GetMaxBufferSize() int32 {
+	if m != nil {
+		return m.MaxBufferSize
+	}
+	return 0
+}
+
 // Describes Wavefront Proxy credentials.
 type Params_WavefrontProxy struct {
-	// The wavefront proxy address. Ex: 192.168.99.100:2878
+	// The wavefront proxy address. The host may be a hostname, an IPv4
+	// address or an IPv6 address in brackets, and the port a number or a
+	// service name. A hostname resolving to several proxies, e.g. a headless
+	// service, spreads the metrics over all of them.
+	// Ex: 192.168.99.100:2878, [fd00::1]:2878
 	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// The wavefront proxy port for histogram distributions, required for
+	// WAVEFRONT_HISTOGRAM metrics. Ex: 40000
+	DistributionPort int32 `protobuf:"varint,2,opt,name=distribution_port,json=distributionPort,proto3" json:"distribution_port,omitempty"`
+	// More wavefront proxy addresses to spread the metrics over, along with
+	// address, which may then be left empty. Ex: 192.168.99.101:2878
+	Addresses []string `protobuf:"bytes,3,rep,name=addresses" json:"addresses,omitempty"`
+	// How the metrics are spread over the proxies. Defaults to ROUND_ROBIN.
+	LoadBalancing Params_WavefrontProxy_LoadBalancing `protobuf:"varint,4,opt,name=load_balancing,json=loadBalancing,proto3,enum=wavefront.config.Params_WavefrontProxy_LoadBalancing" json:"load_balancing,omitempty"`
+	// The number of consecutive errors after which a proxy is ejected, and
+	// its metrics are sent to the other proxies. Defaults to 3.
+	MaxFailures int32 `protobuf:"varint,5,opt,name=max_failures,json=maxFailures,proto3" json:"max_failures,omitempty"`
+	// How long an ejected proxy is left out before it's tried again. A proxy
+	// that fails again is ejected right away, while one that recovers is put
+	// back in. Defaults to 30s.
+	EjectionTime time.Duration `protobuf:"bytes,6,opt,name=ejection_time,json=ejectionTime,stdduration" json:"ejection_time"`
+	// How often the proxy addresses are resolved again, so that the metrics
+	// follow proxies whose IPs change, e.g. behind a headless service. They're
+	// also resolved again when a proxy fails. Defaults to 30s.
+	ResolveInterval time.Duration `protobuf:"bytes,7,opt,name=resolve_interval,json=resolveInterval,stdduration" json:"resolve_interval"`
 }
 
 func (m *Params_WavefrontProxy) Reset()                    { *m = Params_WavefrontProxy{} }
@@ -296,6 +598,48 @@ func (m *Params_WavefrontProxy) GetAddress() string {
 	return ""
 }
 
+func (m *Params_WavefrontProxy) GetDistributionPort() int32 {
+	if m != nil {
+		return m.DistributionPort
+	}
+	return 0
+}
+
+func (m *Params_WavefrontProxy) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *Params_WavefrontProxy) GetLoadBalancing() Params_WavefrontProxy_LoadBalancing {
+	if m != nil {
+		return m.LoadBalancing
+	}
+	return ROUND_ROBIN
+}
+
+func (m *Params_WavefrontProxy) GetMaxFailures() int32 {
+	if m != nil {
+		return m.MaxFailures
+	}
+	return 0
+}
+
+func (m *Params_WavefrontProxy) GetEjectionTime() time.Duration {
+	if m != nil {
+		return m.EjectionTime
+	}
+	return 0
+}
+
+func (m *Params_WavefrontProxy) GetResolveInterval() time.Duration {
+	if m != nil {
+		return m.ResolveInterval
+	}
+	return 0
+}
+
 // Describes how a metric should be represented on Wavefront.
 type Params_MetricInfo struct {
 	// The metric name. (Optional)
@@ -305,7 +649,39 @@ type Params_MetricInfo struct {
 	// The metric type.
 	Type Params_MetricInfo_Type `protobuf:"varint,2,opt,name=type,proto3,enum=wavefront.config.Params_MetricInfo_Type" json:"type,omitempty"`
 	// For metrics with type HISTOGRAM, this describes the sample definition.
+	// For metrics with type TIMER, it's optional and defaults to an
+	// exponentially decaying sample.
 	Sample *Params_MetricInfo_Sample `protobuf:"bytes,3,opt,name=sample" json:"sample,omitempty"`
+	// For metrics with type WAVEFRONT_HISTOGRAM, this describes the interval
+	// distributions are aggregated over.
+	Granularity Params_MetricInfo_Granularity `protobuf:"varint,6,opt,name=granularity,proto3,enum=wavefront.config.Params_MetricInfo_Granularity" json:"granularity,omitempty"`
+	// For metrics with duration values, the unit the durations are reported in.
+	// Timers report their values in this unit as well. The values of all the
+	// metric types but gauges are integers, so only gauges may use SECONDS.
+	DurationUnit Params_MetricInfo_DurationUnit `protobuf:"varint,5,opt,name=duration_unit,json=durationUnit,proto3,enum=wavefront.config.Params_MetricInfo_DurationUnit" json:"duration_unit,omitempty"`
+	// The maximum number of active series of the metric, that is of distinct
+	// tag sets. There is no limit if 0.
+	MaxSeries int32 `protobuf:"varint,7,opt,name=max_series,json=maxSeries,proto3" json:"max_series,omitempty"`
+	// What to do with the instances of new series once the series limit of the
+	// metric, or of the handler, is reached.
+	Overflow Params_MetricInfo_Overflow `protobuf:"varint,8,opt,name=overflow,proto3,enum=wavefront.config.Params_MetricInfo_Overflow" json:"overflow,omitempty"`
+	// The rules turning the instance dimensions into the point tags of the
+	// metric. They take precedence over the handler tag rules: the include and
+	// exclude lists replace the handler ones if not empty, and the renames and
+	// static tags are merged with the handler ones.
+	Tags *Params_TagRules `protobuf:"bytes,9,opt,name=tags" json:"tags,omitempty"`
+	// Whether to convert boolean values to 1 for true and 0 for false, e.g.
+	// for the connection.mtls attribute.
+	ConvertBools bool `protobuf:"varint,10,opt,name=convert_bools,json=convertBools,proto3" json:"convert_bools,omitempty"`
+	// Whether to parse string values as numbers.
+	ParseStrings bool `protobuf:"varint,11,opt,name=parse_strings,json=parseStrings,proto3" json:"parse_strings,omitempty"`
+	// The numbers string values stand for, e.g. for enum-like values. The
+	// values that aren't mapped are parsed if parse_strings is set.
+	// Ex: {UP: 1, DOWN: 0}
+	ValueMap map[string]float64 `protobuf:"bytes,12,rep,name=value_map,json=valueMap" json:"value_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	// For metrics with type GAUGE, how the values received over each flush
+	// interval are aggregated.
+	Aggregation Params_MetricInfo_Aggregation `protobuf:"varint,13,opt,name=aggregation,proto3,enum=wavefront.config.Params_MetricInfo_Aggregation" json:"aggregation,omitempty"`
 }
 
 func (m *Params_MetricInfo) Reset()                    { *m = Params_MetricInfo{} }
@@ -340,6 +716,69 @@ func (m *Params_MetricInfo) GetSample() *Params_MetricInfo_Sample {
 	return nil
 }
 
+func (m *Params_MetricInfo) GetGranularity() Params_MetricInfo_Granularity {
+	if m != nil {
+		return m.Granularity
+	}
+	return MINUTE
+}
+
+func (m *Params_MetricInfo) GetDurationUnit() Params_MetricInfo_DurationUnit {
+	if m != nil {
+		return m.DurationUnit
+	}
+	return NANOSECONDS
+}
+
+func (m *Params_MetricInfo) GetMaxSeries() int32 {
+	if m != nil {
+		return m.MaxSeries
+	}
+	return 0
+}
+
+func (m *Params_MetricInfo) GetOverflow() Params_MetricInfo_Overflow {
+	if m != nil {
+		return m.Overflow
+	}
+	return DROP
+}
+
+func (m *Params_MetricInfo) GetTags() *Params_TagRules {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *Params_MetricInfo) GetConvertBools() bool {
+	if m != nil {
+		return m.ConvertBools
+	}
+	return false
+}
+
+func (m *Params_MetricInfo) GetParseStrings() bool {
+	if m != nil {
+		return m.ParseStrings
+	}
+	return false
+}
+
+func (m *Params_MetricInfo) GetValueMap() map[string]float64 {
+	if m != nil {
+		return m.ValueMap
+	}
+	return nil
+}
+
+func (m *Params_MetricInfo) GetAggregation() Params_MetricInfo_Aggregation {
+	if m != nil {
+		return m.Aggregation
+	}
+	return LAST
+}
+
 // Describes a sample as in the [rcrowley/go-metrics](https://github.com/rcrowley/go-metrics) library.
 type Params_MetricInfo_Sample struct {
 	// One of the sample definition must be supplied.
@@ -515,6 +954,50 @@ func (m *Params_MetricInfo_Sample_Uniform) GetReservoirSize() int32 {
 	return 0
 }
 
+// Describes how instance dimensions become point tags.
+type Params_TagRules struct {
+	// The dimensions to keep. All of them are kept if empty.
+	Include []string `protobuf:"bytes,1,rep,name=include" json:"include,omitempty"`
+	// The dimensions to drop.
+	Exclude []string `protobuf:"bytes,2,rep,name=exclude" json:"exclude,omitempty"`
+	// The tag keys to rename, by dimension. Ex: destination_service: service
+	Rename map[string]string `protobuf:"bytes,3,rep,name=rename" json:"rename,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The static tags to add to every point. Ex: cluster: prod-us-east
+	Add map[string]string `protobuf:"bytes,4,rep,name=add" json:"add,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *Params_TagRules) Reset()                    { *m = Params_TagRules{} }
+func (*Params_TagRules) ProtoMessage()               {}
+func (*Params_TagRules) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{0, 3} }
+
+func (m *Params_TagRules) GetInclude() []string {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+func (m *Params_TagRules) GetExclude() []string {
+	if m != nil {
+		return m.Exclude
+	}
+	return nil
+}
+
+func (m *Params_TagRules) GetRename() map[string]string {
+	if m != nil {
+		return m.Rename
+	}
+	return nil
+}
+
+func (m *Params_TagRules) GetAdd() map[string]string {
+	if m != nil {
+		return m.Add
+	}
+	return nil
+}
+
 // Describes the logging configuration.
 type Params_Logs struct {
 	// The log level (one of error, warn, info, debug, or none). Ex: info
@@ -523,7 +1006,7 @@ type Params_Logs struct {
 
 func (m *Params_Logs) Reset()                    { *m = Params_Logs{} }
 func (*Params_Logs) ProtoMessage()               {}
-func (*Params_Logs) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{0, 3} }
+func (*Params_Logs) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{0, 4} }
 
 func (m *Params_Logs) GetLevel() string {
 	if m != nil {
@@ -532,6 +1015,103 @@ func (m *Params_Logs) GetLevel() string {
 	return ""
 }
 
+// Describes a rewrite of the values of a tag, e.g. to template the
+// identifiers in URL paths.
+type Params_TagRewrite struct {
+	// The dimension whose values are rewritten. Ex: request_path
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// The regular expression matched against the values, in
+	// [RE2 syntax](https://github.com/google/re2/wiki/Syntax). The whole value
+	// is matched if empty. Ex: /users/[0-9]+/
+	Match string `protobuf:"bytes,2,opt,name=match,proto3" json:"match,omitempty"`
+	// The replacement of the matches, which may refer to submatches as $1 or
+	// ${name}. Ex: /users/{id}/
+	Replacement string `protobuf:"bytes,3,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	// Whether to lower-case the rewritten values.
+	Lowercase bool `protobuf:"varint,4,opt,name=lowercase,proto3" json:"lowercase,omitempty"`
+	// The maximum length of the rewritten values, which are truncated beyond
+	// it. There is no limit if 0.
+	MaxLength int32 `protobuf:"varint,5,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"`
+}
+
+func (m *Params_TagRewrite) Reset()                    { *m = Params_TagRewrite{} }
+func (*Params_TagRewrite) ProtoMessage()               {}
+func (*Params_TagRewrite) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{0, 5} }
+
+func (m *Params_TagRewrite) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *Params_TagRewrite) GetMatch() string {
+	if m != nil {
+		return m.Match
+	}
+	return ""
+}
+
+func (m *Params_TagRewrite) GetReplacement() string {
+	if m != nil {
+		return m.Replacement
+	}
+	return ""
+}
+
+func (m *Params_TagRewrite) GetLowercase() bool {
+	if m != nil {
+		return m.Lowercase
+	}
+	return false
+}
+
+func (m *Params_TagRewrite) GetMaxLength() int32 {
+	if m != nil {
+		return m.MaxLength
+	}
+	return 0
+}
+
+type Params_Spool struct {
+	Directory  string        `protobuf:"bytes,1,opt,name=directory,proto3" json:"directory,omitempty"`
+	MaxSize    int64         `protobuf:"varint,2,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	MaxAge     time.Duration `protobuf:"bytes,3,opt,name=max_age,json=maxAge,stdduration" json:"max_age"`
+	ReplayRate int32         `protobuf:"varint,4,opt,name=replay_rate,json=replayRate,proto3" json:"replay_rate,omitempty"`
+}
+
+func (m *Params_Spool) Reset()                    { *m = Params_Spool{} }
+func (*Params_Spool) ProtoMessage()               {}
+func (*Params_Spool) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{0, 7} }
+
+func (m *Params_Spool) GetDirectory() string {
+	if m != nil {
+		return m.Directory
+	}
+	return ""
+}
+
+func (m *Params_Spool) GetMaxSize() int64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *Params_Spool) GetMaxAge() time.Duration {
+	if m != nil {
+		return m.MaxAge
+	}
+	return 0
+}
+
+func (m *Params_Spool) GetReplayRate() int32 {
+	if m != nil {
+		return m.ReplayRate
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Params)(nil), "wavefront.config.Params")
 	proto.RegisterType((*Params_WavefrontDirect)(nil), "wavefront.config.Params.WavefrontDirect")
@@ -540,8 +1120,31 @@ func init() {
 	proto.RegisterType((*Params_MetricInfo_Sample)(nil), "wavefront.config.Params.MetricInfo.Sample")
 	proto.RegisterType((*Params_MetricInfo_Sample_ExpDecay)(nil), "wavefront.config.Params.MetricInfo.Sample.ExpDecay")
 	proto.RegisterType((*Params_MetricInfo_Sample_Uniform)(nil), "wavefront.config.Params.MetricInfo.Sample.Uniform")
+	proto.RegisterType((*Params_TagRules)(nil), "wavefront.config.Params.TagRules")
 	proto.RegisterType((*Params_Logs)(nil), "wavefront.config.Params.Logs")
+	proto.RegisterType((*Params_TagRewrite)(nil), "wavefront.config.Params.TagRewrite")
+	proto.RegisterType((*Params_Spool)(nil), "wavefront.config.Params.Spool")
+	proto.RegisterEnum("wavefront.config.Params_TagFormat", Params_TagFormat_name, Params_TagFormat_value)
+	proto.RegisterEnum("wavefront.config.Params_WavefrontProxy_LoadBalancing", Params_WavefrontProxy_LoadBalancing_name, Params_WavefrontProxy_LoadBalancing_value)
 	proto.RegisterEnum("wavefront.config.Params_MetricInfo_Type", Params_MetricInfo_Type_name, Params_MetricInfo_Type_value)
+	proto.RegisterEnum("wavefront.config.Params_MetricInfo_Granularity", Params_MetricInfo_Granularity_name, Params_MetricInfo_Granularity_value)
+	proto.RegisterEnum("wavefront.config.Params_MetricInfo_DurationUnit", Params_MetricInfo_DurationUnit_name, Params_MetricInfo_DurationUnit_value)
+	proto.RegisterEnum("wavefront.config.Params_MetricInfo_Overflow", Params_MetricInfo_Overflow_name, Params_MetricInfo_Overflow_value)
+	proto.RegisterEnum("wavefront.config.Params_MetricInfo_Aggregation", Params_MetricInfo_Aggregation_name, Params_MetricInfo_Aggregation_value)
+}
+func (x Params_TagFormat) String() string {
+	s, ok := Params_TagFormat_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Params_WavefrontProxy_LoadBalancing) String() string {
+	s, ok := Params_WavefrontProxy_LoadBalancing_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
 func (x Params_MetricInfo_Type) String() string {
 	s, ok := Params_MetricInfo_Type_name[int32(x)]
@@ -550,19 +1153,47 @@ func (x Params_MetricInfo_Type) String() string {
 	}
 	return strconv.Itoa(int(x))
 }
-func (this *Params) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
-
-	that1, ok := that.(*Params)
-	if !ok {
-		that2, ok := that.(Params)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (x Params_MetricInfo_Granularity) String() string {
+	s, ok := Params_MetricInfo_Granularity_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Params_MetricInfo_DurationUnit) String() string {
+	s, ok := Params_MetricInfo_DurationUnit_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Params_MetricInfo_Overflow) String() string {
+	s, ok := Params_MetricInfo_Overflow_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Params_MetricInfo_Aggregation) String() string {
+	s, ok := Params_MetricInfo_Aggregation_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (this *Params) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params)
+	if !ok {
+		that2, ok := that.(Params)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
 	if that1 == nil {
 		return this == nil
@@ -598,6 +1229,37 @@ func (this *Params) Equal(that interface{}) bool {
 	if !this.Logs.Equal(that1.Logs) {
 		return false
 	}
+	if this.AdapterMetricsPrefix != that1.AdapterMetricsPrefix {
+		return false
+	}
+	if this.MaxSeries != that1.MaxSeries {
+		return false
+	}
+	if !this.Tags.Equal(that1.Tags) {
+		return false
+	}
+	if len(this.TagRewrites) != len(that1.TagRewrites) {
+		return false
+	}
+	for i := range this.TagRewrites {
+		if !this.TagRewrites[i].Equal(that1.TagRewrites[i]) {
+			return false
+		}
+	}
+	if len(this.TagFormats) != len(that1.TagFormats) {
+		return false
+	}
+	for i := range this.TagFormats {
+		if this.TagFormats[i] != that1.TagFormats[i] {
+			return false
+		}
+	}
+	if this.SeriesExpiryIntervals != that1.SeriesExpiryIntervals {
+		return false
+	}
+	if !this.Spool.Equal(that1.Spool) {
+		return false
+	}
 	return true
 }
 func (this *Params_Direct) Equal(that interface{}) bool {
@@ -673,6 +1335,9 @@ func (this *Params_WavefrontDirect) Equal(that interface{}) bool {
 	if this.Token != that1.Token {
 		return false
 	}
+	if this.MaxBufferSize != that1.MaxBufferSize {
+		return false
+	}
 	return true
 }
 func (this *Params_WavefrontProxy) Equal(that interface{}) bool {
@@ -697,6 +1362,29 @@ func (this *Params_WavefrontProxy) Equal(that interface{}) bool {
 	if this.Address != that1.Address {
 		return false
 	}
+	if this.DistributionPort != that1.DistributionPort {
+		return false
+	}
+	if len(this.Addresses) != len(that1.Addresses) {
+		return false
+	}
+	for i := range this.Addresses {
+		if this.Addresses[i] != that1.Addresses[i] {
+			return false
+		}
+	}
+	if this.LoadBalancing != that1.LoadBalancing {
+		return false
+	}
+	if this.MaxFailures != that1.MaxFailures {
+		return false
+	}
+	if this.EjectionTime != that1.EjectionTime {
+		return false
+	}
+	if this.ResolveInterval != that1.ResolveInterval {
+		return false
+	}
 	return true
 }
 func (this *Params_MetricInfo) Equal(that interface{}) bool {
@@ -730,6 +1418,38 @@ func (this *Params_MetricInfo) Equal(that interface{}) bool {
 	if !this.Sample.Equal(that1.Sample) {
 		return false
 	}
+	if this.Granularity != that1.Granularity {
+		return false
+	}
+	if this.DurationUnit != that1.DurationUnit {
+		return false
+	}
+	if this.MaxSeries != that1.MaxSeries {
+		return false
+	}
+	if this.Overflow != that1.Overflow {
+		return false
+	}
+	if !this.Tags.Equal(that1.Tags) {
+		return false
+	}
+	if this.ConvertBools != that1.ConvertBools {
+		return false
+	}
+	if this.ParseStrings != that1.ParseStrings {
+		return false
+	}
+	if len(this.ValueMap) != len(that1.ValueMap) {
+		return false
+	}
+	for i := range this.ValueMap {
+		if this.ValueMap[i] != that1.ValueMap[i] {
+			return false
+		}
+	}
+	if this.Aggregation != that1.Aggregation {
+		return false
+	}
 	return true
 }
 func (this *Params_MetricInfo_Sample) Equal(that interface{}) bool {
@@ -861,6 +1581,59 @@ func (this *Params_MetricInfo_Sample_Uniform) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *Params_TagRules) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_TagRules)
+	if !ok {
+		that2, ok := that.(Params_TagRules)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Include) != len(that1.Include) {
+		return false
+	}
+	for i := range this.Include {
+		if this.Include[i] != that1.Include[i] {
+			return false
+		}
+	}
+	if len(this.Exclude) != len(that1.Exclude) {
+		return false
+	}
+	for i := range this.Exclude {
+		if this.Exclude[i] != that1.Exclude[i] {
+			return false
+		}
+	}
+	if len(this.Rename) != len(that1.Rename) {
+		return false
+	}
+	for i := range this.Rename {
+		if this.Rename[i] != that1.Rename[i] {
+			return false
+		}
+	}
+	if len(this.Add) != len(that1.Add) {
+		return false
+	}
+	for i := range this.Add {
+		if this.Add[i] != that1.Add[i] {
+			return false
+		}
+	}
+	return true
+}
 func (this *Params_Logs) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -885,11 +1658,80 @@ func (this *Params_Logs) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *Params_TagRewrite) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_TagRewrite)
+	if !ok {
+		that2, ok := that.(Params_TagRewrite)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Tag != that1.Tag {
+		return false
+	}
+	if this.Match != that1.Match {
+		return false
+	}
+	if this.Replacement != that1.Replacement {
+		return false
+	}
+	if this.Lowercase != that1.Lowercase {
+		return false
+	}
+	if this.MaxLength != that1.MaxLength {
+		return false
+	}
+	return true
+}
+func (this *Params_Spool) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_Spool)
+	if !ok {
+		that2, ok := that.(Params_Spool)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Directory != that1.Directory {
+		return false
+	}
+	if this.MaxSize != that1.MaxSize {
+		return false
+	}
+	if this.MaxAge != that1.MaxAge {
+		return false
+	}
+	if this.ReplayRate != that1.ReplayRate {
+		return false
+	}
+	return true
+}
 func (this *Params) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 11)
+	s := make([]string, 0, 18)
 	s = append(s, "&config.Params{")
 	if this.Credentials != nil {
 		s = append(s, "Credentials: "+fmt.Sprintf("%#v", this.Credentials)+",\n")
@@ -903,6 +1745,31 @@ func (this *Params) GoString() string {
 	if this.Logs != nil {
 		s = append(s, "Logs: "+fmt.Sprintf("%#v", this.Logs)+",\n")
 	}
+	s = append(s, "AdapterMetricsPrefix: "+fmt.Sprintf("%#v", this.AdapterMetricsPrefix)+",\n")
+	s = append(s, "MaxSeries: "+fmt.Sprintf("%#v", this.MaxSeries)+",\n")
+	if this.Tags != nil {
+		s = append(s, "Tags: "+fmt.Sprintf("%#v", this.Tags)+",\n")
+	}
+	if this.TagRewrites != nil {
+		s = append(s, "TagRewrites: "+fmt.Sprintf("%#v", this.TagRewrites)+",\n")
+	}
+	keysForTagFormats := make([]string, 0, len(this.TagFormats))
+	for k, _ := range this.TagFormats {
+		keysForTagFormats = append(keysForTagFormats, k)
+	}
+	sortkeys.Strings(keysForTagFormats)
+	mapStringForTagFormats := "map[string]Params_TagFormat{"
+	for _, k := range keysForTagFormats {
+		mapStringForTagFormats += fmt.Sprintf("%#v: %#v,", k, this.TagFormats[k])
+	}
+	mapStringForTagFormats += "}"
+	if this.TagFormats != nil {
+		s = append(s, "TagFormats: "+mapStringForTagFormats+",\n")
+	}
+	s = append(s, "SeriesExpiryIntervals: "+fmt.Sprintf("%#v", this.SeriesExpiryIntervals)+",\n")
+	if this.Spool != nil {
+		s = append(s, "Spool: "+fmt.Sprintf("%#v", this.Spool)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -926,10 +1793,11 @@ func (this *Params_WavefrontDirect) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 7)
 	s = append(s, "&config.Params_WavefrontDirect{")
 	s = append(s, "Server: "+fmt.Sprintf("%#v", this.Server)+",\n")
 	s = append(s, "Token: "+fmt.Sprintf("%#v", this.Token)+",\n")
+	s = append(s, "MaxBufferSize: "+fmt.Sprintf("%#v", this.MaxBufferSize)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -937,9 +1805,15 @@ func (this *Params_WavefrontProxy) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 5)
+	s := make([]string, 0, 11)
 	s = append(s, "&config.Params_WavefrontProxy{")
 	s = append(s, "Address: "+fmt.Sprintf("%#v", this.Address)+",\n")
+	s = append(s, "DistributionPort: "+fmt.Sprintf("%#v", this.DistributionPort)+",\n")
+	s = append(s, "Addresses: "+fmt.Sprintf("%#v", this.Addresses)+",\n")
+	s = append(s, "LoadBalancing: "+fmt.Sprintf("%#v", this.LoadBalancing)+",\n")
+	s = append(s, "MaxFailures: "+fmt.Sprintf("%#v", this.MaxFailures)+",\n")
+	s = append(s, "EjectionTime: "+fmt.Sprintf("%#v", this.EjectionTime)+",\n")
+	s = append(s, "ResolveInterval: "+fmt.Sprintf("%#v", this.ResolveInterval)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -947,7 +1821,7 @@ func (this *Params_MetricInfo) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 17)
 	s = append(s, "&config.Params_MetricInfo{")
 	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
 	s = append(s, "InstanceName: "+fmt.Sprintf("%#v", this.InstanceName)+",\n")
@@ -955,6 +1829,29 @@ func (this *Params_MetricInfo) GoString() string {
 	if this.Sample != nil {
 		s = append(s, "Sample: "+fmt.Sprintf("%#v", this.Sample)+",\n")
 	}
+	s = append(s, "Granularity: "+fmt.Sprintf("%#v", this.Granularity)+",\n")
+	s = append(s, "DurationUnit: "+fmt.Sprintf("%#v", this.DurationUnit)+",\n")
+	s = append(s, "MaxSeries: "+fmt.Sprintf("%#v", this.MaxSeries)+",\n")
+	s = append(s, "Overflow: "+fmt.Sprintf("%#v", this.Overflow)+",\n")
+	if this.Tags != nil {
+		s = append(s, "Tags: "+fmt.Sprintf("%#v", this.Tags)+",\n")
+	}
+	s = append(s, "ConvertBools: "+fmt.Sprintf("%#v", this.ConvertBools)+",\n")
+	s = append(s, "ParseStrings: "+fmt.Sprintf("%#v", this.ParseStrings)+",\n")
+	keysForValueMap := make([]string, 0, len(this.ValueMap))
+	for k, _ := range this.ValueMap {
+		keysForValueMap = append(keysForValueMap, k)
+	}
+	sortkeys.Strings(keysForValueMap)
+	mapStringForValueMap := "map[string]float64{"
+	for _, k := range keysForValueMap {
+		mapStringForValueMap += fmt.Sprintf("%#v: %#v,", k, this.ValueMap[k])
+	}
+	mapStringForValueMap += "}"
+	if this.ValueMap != nil {
+		s = append(s, "ValueMap: "+mapStringForValueMap+",\n")
+	}
+	s = append(s, "Aggregation: "+fmt.Sprintf("%#v", this.Aggregation)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1007,6 +1904,43 @@ func (this *Params_MetricInfo_Sample_Uniform) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *Params_TagRules) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&config.Params_TagRules{")
+	s = append(s, "Include: "+fmt.Sprintf("%#v", this.Include)+",\n")
+	s = append(s, "Exclude: "+fmt.Sprintf("%#v", this.Exclude)+",\n")
+	keysForRename := make([]string, 0, len(this.Rename))
+	for k, _ := range this.Rename {
+		keysForRename = append(keysForRename, k)
+	}
+	sortkeys.Strings(keysForRename)
+	mapStringForRename := "map[string]string{"
+	for _, k := range keysForRename {
+		mapStringForRename += fmt.Sprintf("%#v: %#v,", k, this.Rename[k])
+	}
+	mapStringForRename += "}"
+	if this.Rename != nil {
+		s = append(s, "Rename: "+mapStringForRename+",\n")
+	}
+	keysForAdd := make([]string, 0, len(this.Add))
+	for k, _ := range this.Add {
+		keysForAdd = append(keysForAdd, k)
+	}
+	sortkeys.Strings(keysForAdd)
+	mapStringForAdd := "map[string]string{"
+	for _, k := range keysForAdd {
+		mapStringForAdd += fmt.Sprintf("%#v: %#v,", k, this.Add[k])
+	}
+	mapStringForAdd += "}"
+	if this.Add != nil {
+		s = append(s, "Add: "+mapStringForAdd+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *Params_Logs) GoString() string {
 	if this == nil {
 		return "nil"
@@ -1017,6 +1951,33 @@ func (this *Params_Logs) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *Params_TagRewrite) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&config.Params_TagRewrite{")
+	s = append(s, "Tag: "+fmt.Sprintf("%#v", this.Tag)+",\n")
+	s = append(s, "Match: "+fmt.Sprintf("%#v", this.Match)+",\n")
+	s = append(s, "Replacement: "+fmt.Sprintf("%#v", this.Replacement)+",\n")
+	s = append(s, "Lowercase: "+fmt.Sprintf("%#v", this.Lowercase)+",\n")
+	s = append(s, "MaxLength: "+fmt.Sprintf("%#v", this.MaxLength)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_Spool) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&config.Params_Spool{")
+	s = append(s, "Directory: "+fmt.Sprintf("%#v", this.Directory)+",\n")
+	s = append(s, "MaxSize: "+fmt.Sprintf("%#v", this.MaxSize)+",\n")
+	s = append(s, "MaxAge: "+fmt.Sprintf("%#v", this.MaxAge)+",\n")
+	s = append(s, "ReplayRate: "+fmt.Sprintf("%#v", this.ReplayRate)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringConfig(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1089,6 +2050,70 @@ func (m *Params) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n3
 	}
+	if len(m.AdapterMetricsPrefix) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.AdapterMetricsPrefix)))
+		i += copy(dAtA[i:], m.AdapterMetricsPrefix)
+	}
+	if m.MaxSeries != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxSeries))
+	}
+	if m.Tags != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Tags.Size()))
+		n4, err := m.Tags.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if len(m.TagRewrites) > 0 {
+		for _, msg := range m.TagRewrites {
+			dAtA[i] = 0x5a
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.TagFormats) > 0 {
+		for k, _ := range m.TagFormats {
+			dAtA[i] = 0x62
+			i++
+			v := m.TagFormats[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + sovConfig(uint64(v))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x10
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(v))
+		}
+	}
+	if m.SeriesExpiryIntervals != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.SeriesExpiryIntervals))
+	}
+	if m.Spool != nil {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Spool.Size()))
+		n5, err := m.Spool.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
 	return i, nil
 }
 
@@ -1098,11 +2123,11 @@ func (m *Params_Direct) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintConfig(dAtA, i, uint64(m.Direct.Size()))
-		n4, err := m.Direct.MarshalTo(dAtA[i:])
+		n6, err := m.Direct.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n4
+		i += n6
 	}
 	return i, nil
 }
@@ -1112,11 +2137,11 @@ func (m *Params_Proxy) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintConfig(dAtA, i, uint64(m.Proxy.Size()))
-		n5, err := m.Proxy.MarshalTo(dAtA[i:])
+		n7, err := m.Proxy.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n5
+		i += n7
 	}
 	return i, nil
 }
@@ -1147,6 +2172,11 @@ func (m *Params_WavefrontDirect) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintConfig(dAtA, i, uint64(len(m.Token)))
 		i += copy(dAtA[i:], m.Token)
 	}
+	if m.MaxBufferSize != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxBufferSize))
+	}
 	return i, nil
 }
 
@@ -1171,12 +2201,58 @@ func (m *Params_WavefrontProxy) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintConfig(dAtA, i, uint64(len(m.Address)))
 		i += copy(dAtA[i:], m.Address)
 	}
-	return i, nil
-}
-
-func (m *Params_MetricInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
+	if m.DistributionPort != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.DistributionPort))
+	}
+	if len(m.Addresses) > 0 {
+		for _, s := range m.Addresses {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.LoadBalancing != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.LoadBalancing))
+	}
+	if m.MaxFailures != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxFailures))
+	}
+	dAtA[i] = 0x32
+	i++
+	i = encodeVarintConfig(dAtA, i, uint64(types.SizeOfStdDuration(m.EjectionTime)))
+	n8, err := types.StdDurationMarshalTo(m.EjectionTime, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n8
+	dAtA[i] = 0x3a
+	i++
+	i = encodeVarintConfig(dAtA, i, uint64(types.SizeOfStdDuration(m.ResolveInterval)))
+	n9, err := types.StdDurationMarshalTo(m.ResolveInterval, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n9
+	return i, nil
+}
+
+func (m *Params_MetricInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
 	if err != nil {
 		return nil, err
@@ -1204,11 +2280,11 @@ func (m *Params_MetricInfo) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintConfig(dAtA, i, uint64(m.Sample.Size()))
-		n6, err := m.Sample.MarshalTo(dAtA[i:])
+		n10, err := m.Sample.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n6
+		i += n10
 	}
 	if len(m.InstanceName) > 0 {
 		dAtA[i] = 0x22
@@ -1216,6 +2292,78 @@ func (m *Params_MetricInfo) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintConfig(dAtA, i, uint64(len(m.InstanceName)))
 		i += copy(dAtA[i:], m.InstanceName)
 	}
+	if m.DurationUnit != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.DurationUnit))
+	}
+	if m.Granularity != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Granularity))
+	}
+	if m.MaxSeries != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxSeries))
+	}
+	if m.Overflow != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Overflow))
+	}
+	if m.Tags != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Tags.Size()))
+		n11, err := m.Tags.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.ConvertBools {
+		dAtA[i] = 0x50
+		i++
+		if m.ConvertBools {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ParseStrings {
+		dAtA[i] = 0x58
+		i++
+		if m.ParseStrings {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.ValueMap) > 0 {
+		for k, _ := range m.ValueMap {
+			dAtA[i] = 0x62
+			i++
+			v := m.ValueMap[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + 8
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x11
+			i++
+			binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(v))))
+			i += 8
+		}
+	}
+	if m.Aggregation != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Aggregation))
+	}
 	return i, nil
 }
 
@@ -1235,11 +2383,11 @@ func (m *Params_MetricInfo_Sample) MarshalTo(dAtA []byte) (int, error) {
 	var l int
 	_ = l
 	if m.Definition != nil {
-		nn7, err := m.Definition.MarshalTo(dAtA[i:])
+		nn12, err := m.Definition.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += nn7
+		i += nn12
 	}
 	return i, nil
 }
@@ -1250,11 +2398,11 @@ func (m *Params_MetricInfo_Sample_ExpDecay_) MarshalTo(dAtA []byte) (int, error)
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintConfig(dAtA, i, uint64(m.ExpDecay.Size()))
-		n8, err := m.ExpDecay.MarshalTo(dAtA[i:])
+		n13, err := m.ExpDecay.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n8
+		i += n13
 	}
 	return i, nil
 }
@@ -1264,11 +2412,11 @@ func (m *Params_MetricInfo_Sample_Uniform_) MarshalTo(dAtA []byte) (int, error)
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintConfig(dAtA, i, uint64(m.Uniform.Size()))
-		n9, err := m.Uniform.MarshalTo(dAtA[i:])
+		n14, err := m.Uniform.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n9
+		i += n14
 	}
 	return i, nil
 }
@@ -1324,6 +2472,88 @@ func (m *Params_MetricInfo_Sample_Uniform) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *Params_TagRules) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_TagRules) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Include) > 0 {
+		for _, s := range m.Include {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Exclude) > 0 {
+		for _, s := range m.Exclude {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Rename) > 0 {
+		for k, _ := range m.Rename {
+			dAtA[i] = 0x1a
+			i++
+			v := m.Rename[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if len(m.Add) > 0 {
+		for k, _ := range m.Add {
+			dAtA[i] = 0x22
+			i++
+			v := m.Add[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	return i, nil
+}
+
 func (m *Params_Logs) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1348,6 +2578,99 @@ func (m *Params_Logs) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *Params_TagRewrite) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_TagRewrite) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Tag) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Tag)))
+		i += copy(dAtA[i:], m.Tag)
+	}
+	if len(m.Match) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Match)))
+		i += copy(dAtA[i:], m.Match)
+	}
+	if len(m.Replacement) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Replacement)))
+		i += copy(dAtA[i:], m.Replacement)
+	}
+	if m.Lowercase {
+		dAtA[i] = 0x20
+		i++
+		if m.Lowercase {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MaxLength != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxLength))
+	}
+	return i, nil
+}
+
+func (m *Params_Spool) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_Spool) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Directory) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Directory)))
+		i += copy(dAtA[i:], m.Directory)
+	}
+	if m.MaxSize != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxSize))
+	}
+	dAtA[i] = 0x1a
+	i++
+	i = encodeVarintConfig(dAtA, i, uint64(types.SizeOfStdDuration(m.MaxAge)))
+	n15, err := types.StdDurationMarshalTo(m.MaxAge, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n15
+	if m.ReplayRate != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ReplayRate))
+	}
+	return i, nil
+}
+
 func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -1383,6 +2706,38 @@ func (m *Params) Size() (n int) {
 		l = m.Logs.Size()
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	l = len(m.AdapterMetricsPrefix)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.MaxSeries != 0 {
+		n += 1 + sovConfig(uint64(m.MaxSeries))
+	}
+	if m.Tags != nil {
+		l = m.Tags.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.TagRewrites) > 0 {
+		for _, e := range m.TagRewrites {
+			l = e.Size()
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.TagFormats) > 0 {
+		for k, v := range m.TagFormats {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + sovConfig(uint64(v))
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if m.SeriesExpiryIntervals != 0 {
+		n += 1 + sovConfig(uint64(m.SeriesExpiryIntervals))
+	}
+	if m.Spool != nil {
+		l = m.Spool.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	return n
 }
 
@@ -1415,6 +2770,9 @@ func (m *Params_WavefrontDirect) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	if m.MaxBufferSize != 0 {
+		n += 1 + sovConfig(uint64(m.MaxBufferSize))
+	}
 	return n
 }
 
@@ -1425,6 +2783,25 @@ func (m *Params_WavefrontProxy) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	if m.DistributionPort != 0 {
+		n += 1 + sovConfig(uint64(m.DistributionPort))
+	}
+	if len(m.Addresses) > 0 {
+		for _, s := range m.Addresses {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.LoadBalancing != 0 {
+		n += 1 + sovConfig(uint64(m.LoadBalancing))
+	}
+	if m.MaxFailures != 0 {
+		n += 1 + sovConfig(uint64(m.MaxFailures))
+	}
+	l = types.SizeOfStdDuration(m.EjectionTime)
+	n += 1 + l + sovConfig(uint64(l))
+	l = types.SizeOfStdDuration(m.ResolveInterval)
+	n += 1 + l + sovConfig(uint64(l))
 	return n
 }
 
@@ -1446,6 +2823,39 @@ func (m *Params_MetricInfo) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	if m.DurationUnit != 0 {
+		n += 1 + sovConfig(uint64(m.DurationUnit))
+	}
+	if m.Granularity != 0 {
+		n += 1 + sovConfig(uint64(m.Granularity))
+	}
+	if m.MaxSeries != 0 {
+		n += 1 + sovConfig(uint64(m.MaxSeries))
+	}
+	if m.Overflow != 0 {
+		n += 1 + sovConfig(uint64(m.Overflow))
+	}
+	if m.Tags != nil {
+		l = m.Tags.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.ConvertBools {
+		n += 2
+	}
+	if m.ParseStrings {
+		n += 2
+	}
+	if len(m.ValueMap) > 0 {
+		for k, v := range m.ValueMap {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + 8
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if m.Aggregation != 0 {
+		n += 1 + sovConfig(uint64(m.Aggregation))
+	}
 	return n
 }
 
@@ -1497,6 +2907,40 @@ func (m *Params_MetricInfo_Sample_Uniform) Size() (n int) {
 	return n
 }
 
+func (m *Params_TagRules) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Include) > 0 {
+		for _, s := range m.Include {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.Exclude) > 0 {
+		for _, s := range m.Exclude {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.Rename) > 0 {
+		for k, v := range m.Rename {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Add) > 0 {
+		for k, v := range m.Add {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
 func (m *Params_Logs) Size() (n int) {
 	var l int
 	_ = l
@@ -1507,6 +2951,48 @@ func (m *Params_Logs) Size() (n int) {
 	return n
 }
 
+func (m *Params_TagRewrite) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Tag)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Match)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Replacement)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.Lowercase {
+		n += 2
+	}
+	if m.MaxLength != 0 {
+		n += 1 + sovConfig(uint64(m.MaxLength))
+	}
+	return n
+}
+
+func (m *Params_Spool) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Directory)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.MaxSize != 0 {
+		n += 1 + sovConfig(uint64(m.MaxSize))
+	}
+	l = types.SizeOfStdDuration(m.MaxAge)
+	n += 1 + l + sovConfig(uint64(l))
+	if m.ReplayRate != 0 {
+		n += 1 + sovConfig(uint64(m.ReplayRate))
+	}
+	return n
+}
+
 func sovConfig(x uint64) (n int) {
 	for {
 		n++
@@ -1524,6 +3010,16 @@ func (this *Params) String() string {
 	if this == nil {
 		return "nil"
 	}
+	keysForTagFormats := make([]string, 0, len(this.TagFormats))
+	for k, _ := range this.TagFormats {
+		keysForTagFormats = append(keysForTagFormats, k)
+	}
+	sortkeys.Strings(keysForTagFormats)
+	mapStringForTagFormats := "map[string]Params_TagFormat{"
+	for _, k := range keysForTagFormats {
+		mapStringForTagFormats += fmt.Sprintf("%v: %v,", k, this.TagFormats[k])
+	}
+	mapStringForTagFormats += "}"
 	s := strings.Join([]string{`&Params{`,
 		`Credentials:` + fmt.Sprintf("%v", this.Credentials) + `,`,
 		`FlushInterval:` + strings.Replace(strings.Replace(this.FlushInterval.String(), "Duration", "google_protobuf1.Duration", 1), `&`, ``, 1) + `,`,
@@ -1531,6 +3027,13 @@ func (this *Params) String() string {
 		`Prefix:` + fmt.Sprintf("%v", this.Prefix) + `,`,
 		`Metrics:` + strings.Replace(fmt.Sprintf("%v", this.Metrics), "Params_MetricInfo", "Params_MetricInfo", 1) + `,`,
 		`Logs:` + strings.Replace(fmt.Sprintf("%v", this.Logs), "Params_Logs", "Params_Logs", 1) + `,`,
+		`AdapterMetricsPrefix:` + fmt.Sprintf("%v", this.AdapterMetricsPrefix) + `,`,
+		`MaxSeries:` + fmt.Sprintf("%v", this.MaxSeries) + `,`,
+		`Tags:` + strings.Replace(fmt.Sprintf("%v", this.Tags), "Params_TagRules", "Params_TagRules", 1) + `,`,
+		`TagRewrites:` + strings.Replace(fmt.Sprintf("%v", this.TagRewrites), "Params_TagRewrite", "Params_TagRewrite", 1) + `,`,
+		`TagFormats:` + mapStringForTagFormats + `,`,
+		`SeriesExpiryIntervals:` + fmt.Sprintf("%v", this.SeriesExpiryIntervals) + `,`,
+		`Spool:` + strings.Replace(fmt.Sprintf("%v", this.Spool), "Params_Spool", "Params_Spool", 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1562,6 +3065,7 @@ func (this *Params_WavefrontDirect) String() string {
 	s := strings.Join([]string{`&Params_WavefrontDirect{`,
 		`Server:` + fmt.Sprintf("%v", this.Server) + `,`,
 		`Token:` + fmt.Sprintf("%v", this.Token) + `,`,
+		`MaxBufferSize:` + fmt.Sprintf("%v", this.MaxBufferSize) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1572,6 +3076,12 @@ func (this *Params_WavefrontProxy) String() string {
 	}
 	s := strings.Join([]string{`&Params_WavefrontProxy{`,
 		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
+		`DistributionPort:` + fmt.Sprintf("%v", this.DistributionPort) + `,`,
+		`Addresses:` + fmt.Sprintf("%v", this.Addresses) + `,`,
+		`LoadBalancing:` + fmt.Sprintf("%v", this.LoadBalancing) + `,`,
+		`MaxFailures:` + fmt.Sprintf("%v", this.MaxFailures) + `,`,
+		`EjectionTime:` + strings.Replace(strings.Replace(this.EjectionTime.String(), "Duration", "google_protobuf1.Duration", 1), `&`, ``, 1) + `,`,
+		`ResolveInterval:` + strings.Replace(strings.Replace(this.ResolveInterval.String(), "Duration", "google_protobuf1.Duration", 1), `&`, ``, 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1580,11 +3090,30 @@ func (this *Params_MetricInfo) String() string {
 	if this == nil {
 		return "nil"
 	}
+	keysForValueMap := make([]string, 0, len(this.ValueMap))
+	for k, _ := range this.ValueMap {
+		keysForValueMap = append(keysForValueMap, k)
+	}
+	sortkeys.Strings(keysForValueMap)
+	mapStringForValueMap := "map[string]float64{"
+	for _, k := range keysForValueMap {
+		mapStringForValueMap += fmt.Sprintf("%v: %v,", k, this.ValueMap[k])
+	}
+	mapStringForValueMap += "}"
 	s := strings.Join([]string{`&Params_MetricInfo{`,
 		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
 		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
 		`Sample:` + strings.Replace(fmt.Sprintf("%v", this.Sample), "Params_MetricInfo_Sample", "Params_MetricInfo_Sample", 1) + `,`,
 		`InstanceName:` + fmt.Sprintf("%v", this.InstanceName) + `,`,
+		`DurationUnit:` + fmt.Sprintf("%v", this.DurationUnit) + `,`,
+		`Granularity:` + fmt.Sprintf("%v", this.Granularity) + `,`,
+		`MaxSeries:` + fmt.Sprintf("%v", this.MaxSeries) + `,`,
+		`Overflow:` + fmt.Sprintf("%v", this.Overflow) + `,`,
+		`Tags:` + strings.Replace(fmt.Sprintf("%v", this.Tags), "Params_TagRules", "Params_TagRules", 1) + `,`,
+		`ConvertBools:` + fmt.Sprintf("%v", this.ConvertBools) + `,`,
+		`ParseStrings:` + fmt.Sprintf("%v", this.ParseStrings) + `,`,
+		`ValueMap:` + mapStringForValueMap + `,`,
+		`Aggregation:` + fmt.Sprintf("%v", this.Aggregation) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1640,6 +3169,39 @@ func (this *Params_MetricInfo_Sample_Uniform) String() string {
 	}, "")
 	return s
 }
+func (this *Params_TagRules) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForRename := make([]string, 0, len(this.Rename))
+	for k, _ := range this.Rename {
+		keysForRename = append(keysForRename, k)
+	}
+	sortkeys.Strings(keysForRename)
+	mapStringForRename := "map[string]string{"
+	for _, k := range keysForRename {
+		mapStringForRename += fmt.Sprintf("%v: %v,", k, this.Rename[k])
+	}
+	mapStringForRename += "}"
+	keysForAdd := make([]string, 0, len(this.Add))
+	for k, _ := range this.Add {
+		keysForAdd = append(keysForAdd, k)
+	}
+	sortkeys.Strings(keysForAdd)
+	mapStringForAdd := "map[string]string{"
+	for _, k := range keysForAdd {
+		mapStringForAdd += fmt.Sprintf("%v: %v,", k, this.Add[k])
+	}
+	mapStringForAdd += "}"
+	s := strings.Join([]string{`&Params_TagRules{`,
+		`Include:` + fmt.Sprintf("%v", this.Include) + `,`,
+		`Exclude:` + fmt.Sprintf("%v", this.Exclude) + `,`,
+		`Rename:` + mapStringForRename + `,`,
+		`Add:` + mapStringForAdd + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *Params_Logs) String() string {
 	if this == nil {
 		return "nil"
@@ -1650,6 +3212,33 @@ func (this *Params_Logs) String() string {
 	}, "")
 	return s
 }
+func (this *Params_TagRewrite) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_TagRewrite{`,
+		`Tag:` + fmt.Sprintf("%v", this.Tag) + `,`,
+		`Match:` + fmt.Sprintf("%v", this.Match) + `,`,
+		`Replacement:` + fmt.Sprintf("%v", this.Replacement) + `,`,
+		`Lowercase:` + fmt.Sprintf("%v", this.Lowercase) + `,`,
+		`MaxLength:` + fmt.Sprintf("%v", this.MaxLength) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_Spool) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_Spool{`,
+		`Directory:` + fmt.Sprintf("%v", this.Directory) + `,`,
+		`MaxSize:` + fmt.Sprintf("%v", this.MaxSize) + `,`,
+		`MaxAge:` + strings.Replace(strings.Replace(this.MaxAge.String(), "Duration", "google_protobuf1.Duration", 1), `&`, ``, 1) + `,`,
+		`ReplayRate:` + fmt.Sprintf("%v", this.ReplayRate) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringConfig(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1903,6 +3492,1057 @@ func (m *Params) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdapterMetricsPrefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AdapterMetricsPrefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSeries", wireType)
+			}
+			m.MaxSeries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSeries |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tags == nil {
+				m.Tags = &Params_TagRules{}
+			}
+			if err := m.Tags.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagRewrites", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagRewrites = append(m.TagRewrites, &Params_TagRewrite{})
+			if err := m.TagRewrites[len(m.TagRewrites)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagFormats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TagFormats == nil {
+				m.TagFormats = make(map[string]Params_TagFormat)
+			}
+			var mapkey string
+			var mapvalue Params_TagFormat
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= (Params_TagFormat(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.TagFormats[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SeriesExpiryIntervals", wireType)
+			}
+			m.SeriesExpiryIntervals = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SeriesExpiryIntervals |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spool", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Spool == nil {
+				m.Spool = &Params_Spool{}
+			}
+			if err := m.Spool.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_WavefrontDirect) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WavefrontDirect: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WavefrontDirect: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Server", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Server = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Token", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Token = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBufferSize", wireType)
+			}
+			m.MaxBufferSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxBufferSize |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_WavefrontProxy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WavefrontProxy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WavefrontProxy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DistributionPort", wireType)
+			}
+			m.DistributionPort = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DistributionPort |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Addresses = append(m.Addresses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LoadBalancing", wireType)
+			}
+			m.LoadBalancing = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LoadBalancing |= (Params_WavefrontProxy_LoadBalancing(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxFailures", wireType)
+			}
+			m.MaxFailures = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxFailures |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EjectionTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := types.StdDurationUnmarshal(&m.EjectionTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResolveInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := types.StdDurationUnmarshal(&m.ResolveInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= (Params_MetricInfo_Type(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sample", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sample == nil {
+				m.Sample = &Params_MetricInfo_Sample{}
+			}
+			if err := m.Sample.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InstanceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.InstanceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationUnit", wireType)
+			}
+			m.DurationUnit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationUnit |= (Params_MetricInfo_DurationUnit(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Granularity", wireType)
+			}
+			m.Granularity = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Granularity |= (Params_MetricInfo_Granularity(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSeries", wireType)
+			}
+			m.MaxSeries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSeries |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Overflow", wireType)
+			}
+			m.Overflow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Overflow |= (Params_MetricInfo_Overflow(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tags == nil {
+				m.Tags = &Params_TagRules{}
+			}
+			if err := m.Tags.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConvertBools", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ConvertBools = bool(v != 0)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ParseStrings", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ParseStrings = bool(v != 0)
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueMap", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ValueMap == nil {
+				m.ValueMap = make(map[string]float64)
+			}
+			var mapkey string
+			var mapvalue float64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapvaluetemp uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvaluetemp = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					mapvalue = math.Float64frombits(mapvaluetemp)
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ValueMap[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregation", wireType)
+			}
+			m.Aggregation = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Aggregation |= (Params_MetricInfo_Aggregation(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -1924,7 +4564,7 @@ func (m *Params) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Params_WavefrontDirect) Unmarshal(dAtA []byte) error {
+func (m *Params_MetricInfo_Sample) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1947,17 +4587,17 @@ func (m *Params_WavefrontDirect) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WavefrontDirect: wiretype end group for non-group")
+			return fmt.Errorf("proto: Sample: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WavefrontDirect: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Sample: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Server", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpDecay", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1967,26 +4607,29 @@ func (m *Params_WavefrontDirect) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Server = string(dAtA[iNdEx:postIndex])
+			v := &Params_MetricInfo_Sample_ExpDecay{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Definition = &Params_MetricInfo_Sample_ExpDecay_{v}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Token", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Uniform", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1996,20 +4639,23 @@ func (m *Params_WavefrontDirect) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Token = string(dAtA[iNdEx:postIndex])
+			v := &Params_MetricInfo_Sample_Uniform{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Definition = &Params_MetricInfo_Sample_Uniform_{v}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2032,7 +4678,7 @@ func (m *Params_WavefrontDirect) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Params_WavefrontProxy) Unmarshal(dAtA []byte) error {
+func (m *Params_MetricInfo_Sample_ExpDecay) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2055,17 +4701,17 @@ func (m *Params_WavefrontProxy) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WavefrontProxy: wiretype end group for non-group")
+			return fmt.Errorf("proto: ExpDecay: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WavefrontProxy: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ExpDecay: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReservoirSize", wireType)
 			}
-			var stringLen uint64
+			m.ReservoirSize = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -2075,21 +4721,22 @@ func (m *Params_WavefrontProxy) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.ReservoirSize |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthConfig
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Alpha", wireType)
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Address = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Alpha = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -2111,7 +4758,7 @@ func (m *Params_WavefrontProxy) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
+func (m *Params_MetricInfo_Sample_Uniform) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2134,17 +4781,17 @@ func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: MetricInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: Uniform: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MetricInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Uniform: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReservoirSize", wireType)
 			}
-			var stringLen uint64
+			m.ReservoirSize = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -2154,45 +4801,66 @@ func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.ReservoirSize |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_TagRules) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
 			}
-			m.Type = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowConfig
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Type |= (Params_MetricInfo_Type(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-		case 3:
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TagRules: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TagRules: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sample", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Include", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -2202,28 +4870,24 @@ func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Sample == nil {
-				m.Sample = &Params_MetricInfo_Sample{}
-			}
-			if err := m.Sample.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Include = append(m.Include, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 4:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InstanceName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Exclude", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2247,62 +4911,12 @@ func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
 			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
-			}
-			m.InstanceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipConfig(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthConfig
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Params_MetricInfo_Sample) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowConfig
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Sample: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Sample: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			}
+			m.Exclude = append(m.Exclude, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExpDecay", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Rename", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2326,15 +4940,101 @@ func (m *Params_MetricInfo_Sample) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &Params_MetricInfo_Sample_ExpDecay{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if m.Rename == nil {
+				m.Rename = make(map[string]string)
 			}
-			m.Definition = &Params_MetricInfo_Sample_ExpDecay_{v}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Rename[mapkey] = mapvalue
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Uniform", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Add", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2358,11 +5058,97 @@ func (m *Params_MetricInfo_Sample) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &Params_MetricInfo_Sample_Uniform{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if m.Add == nil {
+				m.Add = make(map[string]string)
 			}
-			m.Definition = &Params_MetricInfo_Sample_Uniform_{v}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Add[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2385,7 +5171,7 @@ func (m *Params_MetricInfo_Sample) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Params_MetricInfo_Sample_ExpDecay) Unmarshal(dAtA []byte) error {
+func (m *Params_Logs) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2408,17 +5194,17 @@ func (m *Params_MetricInfo_Sample_ExpDecay) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExpDecay: wiretype end group for non-group")
+			return fmt.Errorf("proto: Logs: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExpDecay: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Logs: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReservoirSize", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
 			}
-			m.ReservoirSize = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -2428,22 +5214,21 @@ func (m *Params_MetricInfo_Sample_ExpDecay) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ReservoirSize |= (int32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Alpha", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
 			}
-			var v uint64
-			if (iNdEx + 8) > l {
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.Alpha = float64(math.Float64frombits(v))
+			m.Level = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -2465,7 +5250,7 @@ func (m *Params_MetricInfo_Sample_ExpDecay) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Params_MetricInfo_Sample_Uniform) Unmarshal(dAtA []byte) error {
+func (m *Params_TagRewrite) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2488,17 +5273,104 @@ func (m *Params_MetricInfo_Sample_Uniform) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Uniform: wiretype end group for non-group")
+			return fmt.Errorf("proto: TagRewrite: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Uniform: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TagRewrite: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Match", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Match = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replacement", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Replacement = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReservoirSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Lowercase", wireType)
 			}
-			m.ReservoirSize = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -2508,7 +5380,27 @@ func (m *Params_MetricInfo_Sample_Uniform) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ReservoirSize |= (int32(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Lowercase = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxLength", wireType)
+			}
+			m.MaxLength = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxLength |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2534,7 +5426,7 @@ func (m *Params_MetricInfo_Sample_Uniform) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Params_Logs) Unmarshal(dAtA []byte) error {
+func (m *Params_Spool) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2557,15 +5449,15 @@ func (m *Params_Logs) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Logs: wiretype end group for non-group")
+			return fmt.Errorf("proto: Spool: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Logs: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Spool: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Directory", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2590,8 +5482,76 @@ func (m *Params_Logs) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Level = string(dAtA[iNdEx:postIndex])
+			m.Directory = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSize", wireType)
+			}
+			m.MaxSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSize |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAge", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := types.StdDurationUnmarshal(&m.MaxAge, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplayRate", wireType)
+			}
+			m.ReplayRate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplayRate |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -2721,49 +5681,112 @@ var (
 func init() { proto.RegisterFile("mixer/adapter/wavefront/config/config.proto", fileDescriptorConfig) }
 
 var fileDescriptorConfig = []byte{
-	// 704 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xdd, 0x4e, 0xdb, 0x48,
-	0x14, 0xb6, 0xc1, 0xf9, 0x3b, 0x21, 0xd9, 0xec, 0x08, 0xad, 0xbc, 0xd6, 0xae, 0x41, 0xa0, 0xd5,
-	0x46, 0xac, 0xe4, 0xec, 0xc2, 0x5e, 0xb6, 0x42, 0xa4, 0x89, 0x12, 0x5a, 0x08, 0x68, 0x48, 0x84,
-	0xd4, 0x9b, 0x68, 0x88, 0xc7, 0xc1, 0xaa, 0xe3, 0xb1, 0xc6, 0x4e, 0x9a, 0x70, 0xd5, 0x47, 0xe8,
-	0x65, 0x1f, 0xa1, 0x2f, 0xd0, 0x77, 0xe0, 0xaa, 0xe2, 0xb2, 0x57, 0x6d, 0x49, 0xa5, 0xaa, 0x97,
-	0x3c, 0x42, 0xe5, 0x19, 0x1b, 0x54, 0x24, 0x5a, 0x7a, 0xe5, 0x39, 0xdf, 0x9c, 0xef, 0x3b, 0xdf,
-	0x9c, 0x73, 0x0c, 0xff, 0x8c, 0xdc, 0x29, 0xe5, 0x35, 0x62, 0x93, 0x20, 0xa2, 0xbc, 0xf6, 0x9c,
-	0x4c, 0xa8, 0xc3, 0x99, 0x1f, 0xd5, 0x06, 0xcc, 0x77, 0xdc, 0x61, 0xf2, 0xb1, 0x02, 0xce, 0x22,
-	0x86, 0x2a, 0xd7, 0xd7, 0x96, 0xc4, 0x8d, 0xe5, 0x21, 0x1b, 0x32, 0x71, 0x59, 0x8b, 0x4f, 0x32,
-	0xcf, 0x30, 0x87, 0x8c, 0x0d, 0x3d, 0x5a, 0x13, 0xd1, 0xc9, 0xd8, 0xa9, 0xd9, 0x63, 0x4e, 0x22,
-	0x97, 0xf9, 0xf2, 0x7e, 0xed, 0x73, 0x1e, 0xb2, 0x87, 0x84, 0x93, 0x51, 0x88, 0xea, 0x90, 0xb5,
-	0x5d, 0x4e, 0x07, 0x91, 0xae, 0xae, 0xaa, 0xd5, 0xe2, 0x66, 0xd5, 0xba, 0x5d, 0xc3, 0x92, 0x99,
-	0xd6, 0x71, 0x8a, 0x37, 0x44, 0x7e, 0x5b, 0xc1, 0x09, 0x13, 0x6d, 0x43, 0x26, 0xe0, 0x6c, 0x3a,
-	0xd3, 0x17, 0x84, 0xc4, 0xdf, 0x3f, 0x96, 0x38, 0x8c, 0xd3, 0xdb, 0x0a, 0x96, 0x3c, 0xf4, 0x18,
-	0xca, 0x8e, 0x37, 0x0e, 0x4f, 0xfb, 0xae, 0x1f, 0x51, 0x3e, 0x21, 0x9e, 0xbe, 0x28, 0x94, 0x7e,
-	0xb7, 0xe4, 0x43, 0xac, 0xf4, 0x21, 0x56, 0x23, 0x79, 0x48, 0x3d, 0x7f, 0xfe, 0x7e, 0x45, 0x79,
-	0xf5, 0x61, 0x45, 0xc5, 0x25, 0x41, 0xdd, 0x4d, 0x98, 0xe8, 0x37, 0xc8, 0x86, 0x6c, 0xcc, 0x07,
-	0x54, 0xd7, 0x56, 0xd5, 0x6a, 0x01, 0x27, 0x51, 0x8c, 0x07, 0x9c, 0x3a, 0xee, 0x54, 0xcf, 0x48,
-	0x5c, 0x46, 0xe8, 0x21, 0xe4, 0x46, 0x34, 0xe2, 0xee, 0x20, 0xd4, 0xb3, 0xab, 0x8b, 0xd5, 0xe2,
-	0xe6, 0xfa, 0x9d, 0xf6, 0xf7, 0x45, 0xde, 0xae, 0xef, 0x30, 0x9c, 0x72, 0xd0, 0x7f, 0xa0, 0x79,
-	0x6c, 0x18, 0xea, 0x39, 0x61, 0xf8, 0xcf, 0x3b, 0xb9, 0x7b, 0x6c, 0x18, 0x62, 0x91, 0x6a, 0x6c,
-	0xc3, 0x2f, 0xb7, 0x7a, 0x29, 0x4c, 0x53, 0x3e, 0xa1, 0x5c, 0x4c, 0x21, 0x36, 0x2d, 0x22, 0xb4,
-	0x0c, 0x99, 0x88, 0x3d, 0xa3, 0xbe, 0xe8, 0x6c, 0x01, 0xcb, 0xc0, 0xd8, 0x80, 0xf2, 0xb7, 0x9d,
-	0x44, 0x3a, 0xe4, 0x88, 0x6d, 0x73, 0x1a, 0x86, 0x89, 0x40, 0x1a, 0x1a, 0x6f, 0x35, 0x80, 0x1b,
-	0xdf, 0x08, 0x81, 0xe6, 0x93, 0x11, 0x4d, 0xb2, 0xc4, 0x19, 0x3d, 0x00, 0x2d, 0x9a, 0x05, 0x54,
-	0xd4, 0x28, 0x7f, 0x67, 0x01, 0x6e, 0x64, 0xac, 0xee, 0x2c, 0xa0, 0x58, 0xb0, 0xe2, 0x05, 0x0a,
-	0xc9, 0x28, 0xf0, 0x68, 0x32, 0xb3, 0x8d, 0xfb, 0xf0, 0x8f, 0x04, 0x03, 0x27, 0x4c, 0xb4, 0x0e,
-	0x25, 0xd7, 0x0f, 0x23, 0xe2, 0x0f, 0x68, 0x5f, 0xd8, 0x93, 0xa3, 0x5b, 0x4a, 0xc1, 0x0e, 0x19,
-	0x51, 0xe3, 0xcd, 0x02, 0x64, 0x25, 0x0f, 0x61, 0x28, 0xd0, 0x69, 0xd0, 0xb7, 0xe9, 0x80, 0xcc,
-	0x92, 0xbd, 0xdd, 0xba, 0x7f, 0x59, 0xab, 0x39, 0x0d, 0x1a, 0x31, 0xb5, 0xad, 0xe0, 0x3c, 0x4d,
-	0xce, 0xa8, 0x03, 0xb9, 0xb1, 0xef, 0x3a, 0x8c, 0x8f, 0x92, 0x35, 0xde, 0xfc, 0x09, 0xc5, 0x9e,
-	0x64, 0xb6, 0x15, 0x9c, 0x8a, 0x18, 0x2d, 0xc8, 0xa7, 0x75, 0xd0, 0x5f, 0x50, 0xe6, 0x34, 0x1e,
-	0x29, 0x73, 0x79, 0x3f, 0x74, 0xcf, 0x64, 0xff, 0x33, 0xb8, 0x74, 0x8d, 0x1e, 0xb9, 0x67, 0x34,
-	0x9e, 0x36, 0xf1, 0x82, 0x53, 0x22, 0x0c, 0xa8, 0x58, 0x06, 0xc6, 0xbf, 0x90, 0x4b, 0xe4, 0xef,
-	0xa9, 0x53, 0x5f, 0x02, 0xb0, 0xa9, 0xe3, 0xfa, 0x6e, 0xfc, 0xa7, 0xac, 0xed, 0x83, 0x16, 0x8f,
-	0x0b, 0x15, 0x21, 0xd7, 0xeb, 0x3c, 0xe9, 0x1c, 0x1c, 0x77, 0x2a, 0x0a, 0x2a, 0x40, 0xa6, 0xb5,
-	0xd3, 0x6b, 0x35, 0x2b, 0x6a, 0x8c, 0x3f, 0x3a, 0xe8, 0x75, 0xba, 0x4d, 0x5c, 0x59, 0x40, 0xbf,
-	0x42, 0xa9, 0xd1, 0xdc, 0xeb, 0xee, 0xf4, 0x53, 0x68, 0x11, 0x95, 0xa0, 0xd0, 0xde, 0x3d, 0xea,
-	0x1e, 0xb4, 0xf0, 0xce, 0x7e, 0x45, 0x33, 0xfe, 0x00, 0x2d, 0xde, 0xe5, 0xd8, 0xac, 0x47, 0x27,
-	0xd4, 0x4b, 0x56, 0x49, 0x06, 0xf5, 0x12, 0x14, 0x07, 0x9c, 0xda, 0xd4, 0x8f, 0x5c, 0xe2, 0x85,
-	0xf5, 0xff, 0x2f, 0x2e, 0x4d, 0xe5, 0xdd, 0xa5, 0xa9, 0x5c, 0x5d, 0x9a, 0xea, 0x8b, 0xb9, 0xa9,
-	0xbe, 0x9e, 0x9b, 0xea, 0xf9, 0xdc, 0x54, 0x2f, 0xe6, 0xa6, 0xfa, 0x71, 0x6e, 0xaa, 0x5f, 0xe6,
-	0xa6, 0x72, 0x35, 0x37, 0xd5, 0x97, 0x9f, 0x4c, 0xe5, 0x69, 0x56, 0xb6, 0xf9, 0x24, 0x2b, 0x7e,
-	0xf7, 0xad, 0xaf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe2, 0xe5, 0xa8, 0x88, 0x1c, 0x05, 0x00, 0x00,
+	// 1708 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0x4f, 0x93, 0xe3, 0x46,
+	0x15, 0xb7, 0xfc, 0xdf, 0xcf, 0x7f, 0x46, 0xe9, 0x2c, 0x1b, 0xc5, 0xb5, 0xf1, 0x4e, 0xbc, 0x05,
+	0x4c, 0x91, 0xe0, 0x49, 0x26, 0xd9, 0x54, 0x80, 0xa5, 0x28, 0x7b, 0xc6, 0x3b, 0x36, 0xd8, 0xd2,
+	0xd0, 0xb6, 0xb3, 0x40, 0x51, 0xa5, 0xea, 0xb1, 0xda, 0x5a, 0x11, 0x59, 0x52, 0xb5, 0x64, 0xaf,
+	0xbd, 0xc5, 0x81, 0x8f, 0x40, 0x15, 0x17, 0x6e, 0x5c, 0xf9, 0x02, 0xf0, 0x15, 0xc8, 0x31, 0x55,
+	0x5c, 0x38, 0x01, 0x3b, 0x5c, 0x72, 0xdc, 0x8f, 0x40, 0x75, 0xb7, 0x64, 0x7b, 0xa7, 0xb2, 0x3b,
+	0x9e, 0x93, 0xf5, 0x7e, 0xfd, 0x7e, 0xaf, 0x5f, 0x77, 0xbf, 0xf7, 0xeb, 0x36, 0x7c, 0x30, 0x77,
+	0x56, 0x94, 0x1d, 0x13, 0x8b, 0x04, 0x11, 0x65, 0xc7, 0xcf, 0xc8, 0x92, 0xce, 0x98, 0xef, 0x45,
+	0xc7, 0x53, 0xdf, 0x9b, 0x39, 0x76, 0xfc, 0xd3, 0x0a, 0x98, 0x1f, 0xf9, 0x48, 0xdd, 0x0c, 0xb7,
+	0x24, 0x5e, 0xbf, 0x63, 0xfb, 0xb6, 0x2f, 0x06, 0x8f, 0xf9, 0x97, 0xf4, 0xab, 0x37, 0x6c, 0xdf,
+	0xb7, 0x5d, 0x7a, 0x2c, 0xac, 0xcb, 0xc5, 0xec, 0xd8, 0x5a, 0x30, 0x12, 0x39, 0xbe, 0x27, 0xc7,
+	0x9b, 0xdf, 0xdc, 0x83, 0xfc, 0x05, 0x61, 0x64, 0x1e, 0xa2, 0x0e, 0xe4, 0x2d, 0x87, 0xd1, 0x69,
+	0xa4, 0x29, 0x87, 0xca, 0x51, 0xf9, 0xe4, 0xa8, 0x75, 0x7d, 0x8e, 0x96, 0xf4, 0x6c, 0x3d, 0x49,
+	0xf0, 0x33, 0xe1, 0xdf, 0x4b, 0xe1, 0x98, 0x89, 0x7e, 0x06, 0xb9, 0x80, 0xf9, 0xab, 0xb5, 0x96,
+	0x16, 0x21, 0xbe, 0x7f, 0x73, 0x88, 0x0b, 0xee, 0xde, 0x4b, 0x61, 0xc9, 0x43, 0x3f, 0x87, 0xda,
+	0xcc, 0x5d, 0x84, 0x4f, 0x4d, 0xc7, 0x8b, 0x28, 0x5b, 0x12, 0x57, 0xcb, 0x88, 0x48, 0xef, 0xb6,
+	0xe4, 0x42, 0x5a, 0xc9, 0x42, 0x5a, 0x67, 0xf1, 0x42, 0x3a, 0xc5, 0xaf, 0xfe, 0x7d, 0x3f, 0xf5,
+	0xe7, 0xff, 0xdc, 0x57, 0x70, 0x55, 0x50, 0xfb, 0x31, 0x13, 0xdd, 0x85, 0x7c, 0xe8, 0x2f, 0xd8,
+	0x94, 0x6a, 0xd9, 0x43, 0xe5, 0xa8, 0x84, 0x63, 0x8b, 0xe3, 0x01, 0xa3, 0x33, 0x67, 0xa5, 0xe5,
+	0x24, 0x2e, 0x2d, 0xf4, 0x53, 0x28, 0xcc, 0x69, 0xc4, 0x9c, 0x69, 0xa8, 0xe5, 0x0f, 0x33, 0x47,
+	0xe5, 0x93, 0x07, 0xaf, 0x4d, 0x7f, 0x28, 0xfc, 0xfa, 0xde, 0xcc, 0xc7, 0x09, 0x07, 0x7d, 0x0c,
+	0x59, 0xd7, 0xb7, 0x43, 0xad, 0x20, 0x12, 0x7e, 0xef, 0xb5, 0xdc, 0x81, 0x6f, 0x87, 0x58, 0xb8,
+	0xa2, 0x4f, 0xe1, 0x6e, 0x7c, 0xdc, 0x66, 0x1c, 0xc5, 0x8c, 0x33, 0x2b, 0x8a, 0xcc, 0xee, 0xc4,
+	0xa3, 0x72, 0xba, 0xf0, 0x42, 0xe6, 0xf9, 0x1e, 0xc0, 0x9c, 0xac, 0xcc, 0x90, 0x32, 0x87, 0x86,
+	0x5a, 0xe9, 0x50, 0x39, 0xca, 0xe1, 0xd2, 0x9c, 0xac, 0x46, 0x02, 0x40, 0x0f, 0x21, 0x1b, 0x11,
+	0x3b, 0xd4, 0x40, 0xe4, 0xf1, 0xfe, 0x6b, 0xf3, 0x18, 0x13, 0x1b, 0x2f, 0x5c, 0x1a, 0x62, 0xe1,
+	0x8e, 0x1e, 0x43, 0x25, 0x22, 0xb6, 0xc9, 0xe8, 0x33, 0xe6, 0x44, 0x34, 0xd4, 0xca, 0x37, 0x6c,
+	0x01, 0xa7, 0x4b, 0x5f, 0x5c, 0x8e, 0x36, 0xdf, 0x21, 0xea, 0x03, 0x37, 0xcd, 0x99, 0xcf, 0xe6,
+	0x24, 0x0a, 0xb5, 0xca, 0x61, 0xe6, 0x8d, 0xb5, 0x34, 0x26, 0xf6, 0x63, 0xe9, 0xda, 0xf5, 0x22,
+	0xb6, 0xc6, 0x10, 0x6d, 0x00, 0xf4, 0x19, 0xbc, 0x23, 0x17, 0x69, 0xd2, 0x55, 0xe0, 0xb0, 0xf5,
+	0xa6, 0x28, 0x42, 0xad, 0x2a, 0x56, 0xfd, 0x1d, 0x39, 0xdc, 0x15, 0xa3, 0xc9, 0xb9, 0xf3, 0x6d,
+	0xcd, 0x85, 0x81, 0xef, 0xbb, 0x5a, 0x4d, 0x6c, 0x41, 0xe3, 0xb5, 0x93, 0x8f, 0xb8, 0x17, 0x96,
+	0xce, 0x75, 0x1b, 0x0e, 0xae, 0x15, 0xb6, 0xa8, 0x20, 0xca, 0x96, 0x94, 0x69, 0x4a, 0x5c, 0x41,
+	0xc2, 0x42, 0x77, 0x20, 0x17, 0xf9, 0x5f, 0x52, 0x4f, 0x94, 0x79, 0x09, 0x4b, 0x03, 0x7d, 0x0f,
+	0x0e, 0xf8, 0xb9, 0x5c, 0x2e, 0x66, 0x33, 0xca, 0xcc, 0xd0, 0x79, 0x4e, 0x45, 0xf1, 0xe6, 0x70,
+	0x75, 0x4e, 0x56, 0x1d, 0x81, 0x8e, 0x9c, 0xe7, 0xb4, 0xfe, 0x8f, 0x0c, 0xd4, 0x5e, 0xad, 0x7f,
+	0xa4, 0x41, 0x81, 0x58, 0x16, 0xa3, 0x61, 0x18, 0xcf, 0x94, 0x98, 0xe8, 0x03, 0x78, 0xcb, 0x72,
+	0xc2, 0x88, 0x39, 0x97, 0x0b, 0x5e, 0xed, 0x66, 0xe0, 0xb3, 0x48, 0x4c, 0x9b, 0xc3, 0xea, 0xee,
+	0xc0, 0x85, 0xcf, 0x22, 0x74, 0x0f, 0x4a, 0x31, 0x8f, 0x86, 0x5a, 0xe6, 0x30, 0x73, 0x54, 0xc2,
+	0x5b, 0x00, 0xfd, 0x16, 0x6a, 0xae, 0x4f, 0x2c, 0xf3, 0x92, 0xb8, 0xc4, 0x9b, 0x3a, 0x9e, 0x2d,
+	0xfa, 0xa2, 0x76, 0xf2, 0x70, 0xcf, 0x2e, 0x6d, 0x0d, 0x7c, 0x62, 0x75, 0x12, 0x32, 0xae, 0xba,
+	0xbb, 0x26, 0x7a, 0x1f, 0x2a, 0x7c, 0xf5, 0x33, 0xe2, 0xb8, 0x0b, 0x46, 0x43, 0xd1, 0x5b, 0x39,
+	0x5c, 0x9e, 0x93, 0xd5, 0xe3, 0x18, 0x42, 0x3d, 0xa8, 0xd2, 0xdf, 0xd1, 0xa9, 0x58, 0x47, 0xe4,
+	0xcc, 0xa9, 0x96, 0xdf, 0xbf, 0xb7, 0x2b, 0x09, 0x73, 0xec, 0xcc, 0x29, 0xd2, 0x41, 0x65, 0x34,
+	0xf4, 0xdd, 0x25, 0xdd, 0x0a, 0x45, 0x61, 0xff, 0x60, 0x07, 0x31, 0x39, 0x29, 0x99, 0xe6, 0x43,
+	0xa8, 0xbe, 0xb2, 0x38, 0x74, 0x00, 0x65, 0x6c, 0x4c, 0xf4, 0x33, 0x13, 0x1b, 0x9d, 0xbe, 0xae,
+	0xa6, 0xd0, 0xdb, 0x70, 0x70, 0x6a, 0xe8, 0xa3, 0xfe, 0x68, 0xdc, 0xd5, 0xc7, 0x66, 0xaf, 0x3d,
+	0xea, 0xa9, 0x4a, 0xfd, 0x9f, 0x65, 0x80, 0xad, 0x14, 0x20, 0x04, 0x59, 0x8f, 0xcc, 0x69, 0x7c,
+	0x84, 0xe2, 0x1b, 0x3d, 0x82, 0x6c, 0xb4, 0x0e, 0xa8, 0x38, 0xb2, 0xda, 0x1b, 0xfa, 0x60, 0x1b,
+	0xa6, 0x35, 0x5e, 0x07, 0x14, 0x0b, 0x16, 0xd7, 0xe4, 0x90, 0xcc, 0x03, 0x97, 0xc6, 0x32, 0xf8,
+	0x83, 0x7d, 0xf8, 0x23, 0xc1, 0xc0, 0x31, 0x13, 0x3d, 0x80, 0xaa, 0xe3, 0x85, 0x11, 0xf1, 0xa6,
+	0xd4, 0x14, 0xe9, 0x49, 0x35, 0xac, 0x24, 0xa0, 0xce, 0xd3, 0x9c, 0x40, 0x35, 0xb9, 0x19, 0xcc,
+	0x85, 0xe7, 0x44, 0xe2, 0xf8, 0x6a, 0x27, 0x1f, 0xed, 0x33, 0x5f, 0xb2, 0xc1, 0x13, 0xcf, 0x89,
+	0x70, 0xc5, 0xda, 0xb1, 0xd0, 0x2f, 0xa1, 0x6c, 0x33, 0xe2, 0x2d, 0x5c, 0xc2, 0x9c, 0x68, 0x2d,
+	0xce, 0xbb, 0x76, 0x72, 0xbc, 0x4f, 0xd0, 0xf3, 0x2d, 0x0d, 0xef, 0xc6, 0xb8, 0xa6, 0x7e, 0x85,
+	0xeb, 0xea, 0xd7, 0x83, 0xa2, 0xbf, 0xa4, 0x6c, 0xe6, 0xfa, 0xcf, 0x84, 0x88, 0xd6, 0x4e, 0x3e,
+	0xdc, 0x67, 0x3a, 0x23, 0xe6, 0xe0, 0x0d, 0x7b, 0xa3, 0xa3, 0xa5, 0xdb, 0xe9, 0xe8, 0x03, 0xa8,
+	0x4e, 0x7d, 0x6f, 0x49, 0x59, 0x64, 0x5e, 0xfa, 0xbe, 0x2b, 0x75, 0xb8, 0x88, 0x2b, 0x31, 0xd8,
+	0xe1, 0x18, 0x77, 0x0a, 0x08, 0x0b, 0xa9, 0xc9, 0x1b, 0xd8, 0xb3, 0xb9, 0xda, 0x0a, 0x27, 0x01,
+	0x8e, 0x24, 0x86, 0x74, 0x28, 0x2d, 0x89, 0xbb, 0xa0, 0xe6, 0x9c, 0x04, 0xb1, 0x8e, 0x7e, 0xbc,
+	0xcf, 0x5a, 0xbe, 0xe0, 0xa4, 0x21, 0x09, 0xa4, 0xa0, 0x16, 0x97, 0xb1, 0xc9, 0x0f, 0x83, 0xd8,
+	0x36, 0xa3, 0xb6, 0x38, 0x1f, 0xad, 0xba, 0xff, 0x61, 0xb4, 0xb7, 0x34, 0xbc, 0x1b, 0xa3, 0xfe,
+	0xb7, 0x34, 0xe4, 0x65, 0xb9, 0x21, 0x0c, 0x25, 0xba, 0x0a, 0x4c, 0x8b, 0x4e, 0xc9, 0x3a, 0x7e,
+	0x41, 0x7c, 0xb2, 0x7f, 0xb5, 0xb6, 0xba, 0xab, 0xe0, 0x8c, 0x53, 0x7b, 0x29, 0x5c, 0xa4, 0xf1,
+	0x37, 0xd2, 0xa1, 0xb0, 0xf0, 0x1c, 0x7e, 0x95, 0xc4, 0x0f, 0x8a, 0x93, 0x5b, 0x44, 0x9c, 0x48,
+	0x66, 0x2f, 0x85, 0x93, 0x20, 0xf5, 0x73, 0x28, 0x26, 0xf3, 0xa0, 0xef, 0x42, 0x8d, 0x51, 0xae,
+	0xe7, 0xbe, 0x13, 0x8b, 0xb5, 0x22, 0xc5, 0x7a, 0x83, 0x72, 0xb1, 0xe6, 0x52, 0x4f, 0xdc, 0xe0,
+	0x29, 0x11, 0x09, 0x28, 0x58, 0x1a, 0xf5, 0x8f, 0xa0, 0x10, 0x87, 0xdf, 0x33, 0x4e, 0xa7, 0x02,
+	0x60, 0xd1, 0x99, 0xe3, 0x39, 0x62, 0xdf, 0x7e, 0x02, 0xd5, 0x57, 0x4e, 0x09, 0xa9, 0x90, 0xf9,
+	0x92, 0xae, 0x63, 0xe5, 0xe0, 0x9f, 0x7c, 0x62, 0x71, 0x72, 0xc9, 0xc4, 0xc2, 0xf8, 0x71, 0xfa,
+	0x73, 0xa5, 0xf9, 0x7b, 0xc8, 0x72, 0x89, 0x40, 0x65, 0x28, 0x4c, 0xf4, 0x5f, 0xe8, 0xc6, 0x13,
+	0xae, 0x4f, 0x25, 0xc8, 0x9d, 0xb7, 0x27, 0xe7, 0x5d, 0x55, 0xe1, 0xf8, 0xa9, 0x31, 0xd1, 0xc7,
+	0x5d, 0xac, 0xa6, 0xd1, 0x5b, 0x50, 0x3d, 0xeb, 0x0e, 0xc6, 0x6d, 0x33, 0x81, 0x32, 0xa8, 0x0a,
+	0xa5, 0x5e, 0x7f, 0x34, 0x36, 0xce, 0x71, 0x7b, 0xa8, 0x66, 0xd1, 0x3b, 0xf0, 0xf6, 0x93, 0xf6,
+	0x17, 0xdd, 0xc7, 0xd8, 0xe0, 0xc2, 0xb6, 0x19, 0xc8, 0xf1, 0x90, 0xe3, 0xfe, 0xb0, 0x8b, 0xd5,
+	0x3c, 0xff, 0x1c, 0x76, 0x39, 0xbb, 0xd0, 0xfc, 0x10, 0xca, 0x3b, 0xbd, 0x89, 0x00, 0xf2, 0xc3,
+	0xbe, 0x3e, 0x19, 0x77, 0xd5, 0x14, 0x2a, 0x42, 0xb6, 0x67, 0x4c, 0xb0, 0xaa, 0xa0, 0x02, 0x64,
+	0xce, 0xda, 0xbf, 0x56, 0xd3, 0xcd, 0x0b, 0xa8, 0xec, 0xca, 0x03, 0xd7, 0x55, 0xbd, 0xad, 0x1b,
+	0xa3, 0xee, 0xa9, 0xa1, 0x9f, 0x8d, 0xd4, 0x14, 0x52, 0xa1, 0x32, 0xec, 0x9f, 0xe2, 0x0d, 0xa2,
+	0x48, 0x64, 0x30, 0xe8, 0x27, 0x48, 0x9a, 0x2f, 0x28, 0x31, 0x32, 0xcd, 0x26, 0x14, 0x93, 0x66,
+	0xe5, 0x13, 0x9e, 0x61, 0xe3, 0x42, 0x4d, 0xa1, 0x0a, 0x14, 0x4f, 0x8d, 0xc1, 0xa0, 0x7d, 0x31,
+	0xea, 0xaa, 0x4a, 0xf3, 0x11, 0x94, 0x77, 0x4a, 0x96, 0xbb, 0x0d, 0xda, 0xa3, 0xb1, 0x9a, 0xe2,
+	0x79, 0x0d, 0xdb, 0xbf, 0x92, 0x09, 0x0e, 0xfb, 0xba, 0x9a, 0xe6, 0x1f, 0xa3, 0xc9, 0x50, 0xcd,
+	0x70, 0xa7, 0x61, 0xb7, 0xad, 0xab, 0xd9, 0xfa, 0xdf, 0xd3, 0x50, 0x4c, 0x9a, 0x9a, 0xdf, 0xcc,
+	0x8e, 0x37, 0x75, 0x17, 0x16, 0x3f, 0x57, 0x7e, 0xa1, 0x26, 0x26, 0x1f, 0xa1, 0x2b, 0x39, 0x92,
+	0x96, 0x23, 0xb1, 0x89, 0xba, 0x90, 0x67, 0x54, 0x48, 0x6d, 0x46, 0x74, 0xed, 0x0f, 0x6f, 0xd4,
+	0x8e, 0x16, 0x16, 0xfe, 0xb2, 0x63, 0x63, 0x32, 0x7a, 0x04, 0x19, 0x62, 0x59, 0x5a, 0xf6, 0x30,
+	0xf3, 0x46, 0xe5, 0xdf, 0xc4, 0x68, 0x5b, 0x96, 0x0c, 0xc0, 0x69, 0xf5, 0x1f, 0x41, 0x79, 0x27,
+	0xe8, 0x4d, 0x05, 0x56, 0xda, 0x29, 0xb0, 0xfa, 0x67, 0x50, 0x4c, 0x62, 0xdd, 0x8a, 0x77, 0x0f,
+	0xb2, 0xfc, 0x71, 0xcb, 0x3d, 0x5c, 0xba, 0xa4, 0x6e, 0xcc, 0x92, 0x46, 0xfd, 0x4f, 0x0a, 0xc0,
+	0xf6, 0xd1, 0xc8, 0x03, 0x47, 0xc4, 0x4e, 0x02, 0x47, 0xc4, 0xe6, 0xb4, 0x39, 0x89, 0xa6, 0x4f,
+	0x93, 0xc0, 0xc2, 0x40, 0x87, 0x50, 0x66, 0x34, 0x70, 0xc9, 0x94, 0xce, 0xa9, 0x17, 0x89, 0x7b,
+	0xb0, 0x84, 0x77, 0x21, 0xfe, 0xea, 0x71, 0xfd, 0x67, 0x94, 0x4d, 0x49, 0x28, 0x2f, 0xb7, 0x22,
+	0xde, 0x02, 0xc9, 0x7d, 0xe1, 0x52, 0xcf, 0x8e, 0x9e, 0x6a, 0xb9, 0xcd, 0x7d, 0x31, 0x10, 0x40,
+	0x9d, 0xc0, 0xc1, 0xb5, 0x27, 0xe8, 0xb7, 0x2c, 0xf9, 0xf3, 0xdd, 0x25, 0xd7, 0x4e, 0x9a, 0x37,
+	0xbf, 0x66, 0x77, 0xb7, 0xe5, 0x2f, 0x0a, 0xe4, 0xc4, 0x4b, 0x93, 0x67, 0x2a, 0xff, 0x28, 0xf9,
+	0x2c, 0x89, 0xbf, 0x05, 0xd0, 0xbb, 0x50, 0x14, 0x37, 0x9b, 0xf3, 0x5c, 0x4e, 0x94, 0xc1, 0x05,
+	0x7e, 0xaf, 0x71, 0x15, 0x7a, 0x04, 0xfc, 0xd3, 0x24, 0x36, 0xbd, 0xcd, 0xff, 0xa1, 0xfc, 0x9c,
+	0xac, 0xda, 0x36, 0x45, 0xf7, 0xe3, 0x2d, 0x5c, 0x9b, 0x8c, 0x44, 0x72, 0x8b, 0x72, 0x18, 0x24,
+	0x84, 0x49, 0x44, 0x9b, 0x63, 0x28, 0x6d, 0x32, 0xe7, 0x8d, 0xd0, 0x9e, 0x8c, 0x0d, 0x35, 0xc5,
+	0xb5, 0xa3, 0x7b, 0x61, 0x9c, 0xf6, 0xcc, 0x6d, 0x73, 0xde, 0x05, 0x24, 0xa1, 0x6b, 0x2d, 0x8a,
+	0xa0, 0x76, 0x6a, 0x0c, 0x0c, 0xdd, 0xd4, 0x8d, 0x71, 0x7b, 0xdc, 0x37, 0x74, 0x35, 0xd3, 0xa9,
+	0x42, 0x79, 0xca, 0xa8, 0x45, 0xbd, 0xc8, 0x21, 0x6e, 0xd8, 0xf9, 0xf4, 0xeb, 0x17, 0x8d, 0xd4,
+	0xbf, 0x5e, 0x34, 0x52, 0x2f, 0x5f, 0x34, 0x94, 0x3f, 0x5c, 0x35, 0x94, 0xbf, 0x5e, 0x35, 0x94,
+	0xaf, 0xae, 0x1a, 0xca, 0xd7, 0x57, 0x0d, 0xe5, 0xbf, 0x57, 0x0d, 0xe5, 0x9b, 0xab, 0x46, 0xea,
+	0xe5, 0x55, 0x43, 0xf9, 0xe3, 0xff, 0x1a, 0xa9, 0xdf, 0xe4, 0xe5, 0xc6, 0x5e, 0xe6, 0xc5, 0x02,
+	0x3f, 0xf9, 0xff, 0x00, 0xf9, 0x81, 0x99, 0x8b, 0x1e, 0x0f, 0x00, 0x00,
 }
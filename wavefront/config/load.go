@@ -0,0 +1,67 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+// envReference matches the ${NAME} environment variable references in a
+// configuration file. The bare $NAME form isn't expanded so that the values
+// may contain literal dollar signs.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Parse parses adapter parameters in YAML or JSON format, laid out the same
+// way as the params of a Mixer handler.
+func Parse(data []byte) (*Params, error) {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Params{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(data), cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Load reads adapter parameters from the given file. The ${NAME} references
+// in the file are replaced with the value of the NAME environment variable,
+// so that the API token, for instance, may be kept out of the file.
+func Load(path string) (*Params, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data = envReference.ReplaceAllFunc(data, func(ref []byte) []byte {
+		return []byte(os.Getenv(string(envReference.FindSubmatch(ref)[1])))
+	})
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %v", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+)
+
+func TestParse(t *testing.T) {
+	table := []struct {
+		data   string
+		params *config.Params
+		valid  bool
+	}{
+		{`
+direct:
+  server: https://YOUR-INSTANCE.wavefront.com
+  token: YOUR-API-TOKEN
+flushInterval: 10s
+source: istio
+metrics:
+- name: requestcount
+  instanceName: requestcount.instance.istio-system
+  type: DELTA_COUNTER
+`, &config.Params{
+			Credentials: &config.Params_Direct{Direct: &config.Params_WavefrontDirect{
+				Server: "https://YOUR-INSTANCE.wavefront.com",
+				Token:  "YOUR-API-TOKEN",
+			}},
+			FlushInterval: 10 * time.Second,
+			Source:        "istio",
+			Metrics: []*config.Params_MetricInfo{{
+				Name:         "requestcount",
+				InstanceName: "requestcount.instance.istio-system",
+				Type:         config.DELTA_COUNTER,
+			}},
+		}, true},
+		{`{"proxy": {"address": "wavefront-proxy:2878"}}`, &config.Params{
+			Credentials: &config.Params_Proxy{Proxy: &config.Params_WavefrontProxy{Address: "wavefront-proxy:2878"}},
+		}, true},
+		{"source: [istio", nil, false},
+		{"unknownField: value", nil, false},
+		{"metrics: [{type: UNKNOWN_TYPE}]", nil, false},
+	}
+
+	for _, entry := range table {
+		params, err := config.Parse([]byte(entry.data))
+		if (err == nil) != entry.valid {
+			t.Errorf("Parsing failed for %q, got: %v, want valid: %v.", entry.data, err, entry.valid)
+			continue
+		}
+		if entry.valid && !params.Equal(entry.params) {
+			t.Errorf("Parsing failed for %q, got: %v, want: %v.", entry.data, params, entry.params)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "params.yaml")
+	data := "direct: {server: \"https://${WAVEFRONT_TEST_SERVER}\", token: \"${WAVEFRONT_TEST_TOKEN}\"}\nprefix: $prefix\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("WAVEFRONT_TEST_SERVER", "istio.wavefront.com")
+	os.Setenv("WAVEFRONT_TEST_TOKEN", "secret")
+	defer os.Unsetenv("WAVEFRONT_TEST_SERVER")
+	defer os.Unsetenv("WAVEFRONT_TEST_TOKEN")
+
+	params, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if direct := params.GetDirect(); direct.Server != "https://istio.wavefront.com" || direct.Token != "secret" {
+		t.Errorf("Expected the environment variables to be expanded, got: %v.", direct)
+	}
+	if params.Prefix != "$prefix" {
+		t.Errorf("Expected bare references to be kept, got: %v.", params.Prefix)
+	}
+
+	if _, err := config.Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("Expected an error for a missing file.")
+	}
+}
@@ -15,6 +15,31 @@
 
 package config
 
+import "time"
+
+const (
+	// DefaultFlushInterval is the flush interval used when none is configured.
+	DefaultFlushInterval = 5 * time.Second
+	// MinFlushInterval is the smallest flush interval accepted in the configuration.
+	MinFlushInterval = time.Second
+	// DefaultAdapterMetricsPrefix is the adapter metrics prefix used when none is configured.
+	DefaultAdapterMetricsPrefix = "adapter."
+	// DefaultMaxBufferSize is the direct sender buffer size used when none is configured.
+	DefaultMaxBufferSize = 50000
+	// DefaultProxyMaxFailures is the number of consecutive errors ejecting a proxy used when none is configured.
+	DefaultProxyMaxFailures = 3
+	// DefaultProxyEjectionTime is the proxy ejection time used when none is configured.
+	DefaultProxyEjectionTime = 30 * time.Second
+	// DefaultProxyResolveInterval is the proxy resolve interval used when none is configured.
+	DefaultProxyResolveInterval = 30 * time.Second
+	// DefaultSpoolMaxSize is the spool size limit used when none is configured.
+	DefaultSpoolMaxSize = 100 << 20
+	// DefaultSpoolMaxAge is the spool age limit used when none is configured.
+	DefaultSpoolMaxAge = time.Hour
+	// DefaultSpoolReplayRate is the spool replay rate used when none is configured.
+	DefaultSpoolReplayRate = 1000
+)
+
 // MetricName returns the name for the given metric instance.
 func MetricName(m *Params_MetricInfo) string {
 	name := m.Name
@@ -23,3 +48,87 @@ func MetricName(m *Params_MetricInfo) string {
 	}
 	return name
 }
+
+// FlushInterval returns the flush interval for the given configuration.
+func FlushInterval(cfg *Params) time.Duration {
+	if cfg.FlushInterval == 0 {
+		return DefaultFlushInterval
+	}
+	return cfg.FlushInterval
+}
+
+// AdapterMetricsPrefix returns the prefix of the adapter metrics for the given configuration.
+func AdapterMetricsPrefix(cfg *Params) string {
+	if cfg.AdapterMetricsPrefix == "" {
+		return DefaultAdapterMetricsPrefix
+	}
+	return cfg.AdapterMetricsPrefix
+}
+
+// MaxBufferSize returns the buffer size of the given direct sender configuration.
+func MaxBufferSize(direct *Params_WavefrontDirect) int {
+	if direct.MaxBufferSize == 0 {
+		return DefaultMaxBufferSize
+	}
+	return int(direct.MaxBufferSize)
+}
+
+// ProxyAddresses returns the addresses of the given proxy configuration.
+func ProxyAddresses(proxy *Params_WavefrontProxy) []string {
+	var addresses []string
+	for _, address := range append([]string{proxy.Address}, proxy.Addresses...) {
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// ProxyMaxFailures returns the number of consecutive errors ejecting a proxy
+// of the given proxy configuration.
+func ProxyMaxFailures(proxy *Params_WavefrontProxy) int {
+	if proxy.MaxFailures == 0 {
+		return DefaultProxyMaxFailures
+	}
+	return int(proxy.MaxFailures)
+}
+
+// ProxyEjectionTime returns the ejection time of the given proxy configuration.
+func ProxyEjectionTime(proxy *Params_WavefrontProxy) time.Duration {
+	if proxy.EjectionTime == 0 {
+		return DefaultProxyEjectionTime
+	}
+	return proxy.EjectionTime
+}
+
+// ProxyResolveInterval returns the resolve interval of the given proxy configuration.
+func ProxyResolveInterval(proxy *Params_WavefrontProxy) time.Duration {
+	if proxy.ResolveInterval == 0 {
+		return DefaultProxyResolveInterval
+	}
+	return proxy.ResolveInterval
+}
+
+// SpoolMaxSize returns the size limit of the given spool configuration.
+func SpoolMaxSize(spool *Params_Spool) int64 {
+	if spool.MaxSize == 0 {
+		return DefaultSpoolMaxSize
+	}
+	return spool.MaxSize
+}
+
+// SpoolMaxAge returns the age limit of the given spool configuration.
+func SpoolMaxAge(spool *Params_Spool) time.Duration {
+	if spool.MaxAge == 0 {
+		return DefaultSpoolMaxAge
+	}
+	return spool.MaxAge
+}
+
+// SpoolReplayRate returns the replay rate of the given spool configuration.
+func SpoolReplayRate(spool *Params_Spool) int {
+	if spool.ReplayRate == 0 {
+		return DefaultSpoolReplayRate
+	}
+	return int(spool.ReplayRate)
+}
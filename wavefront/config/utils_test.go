@@ -16,7 +16,9 @@
 package config_test
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
 )
@@ -36,3 +38,91 @@ func TestMetricInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestFlushInterval(t *testing.T) {
+	table := []struct {
+		params        *config.Params
+		flushInterval time.Duration
+	}{
+		{&config.Params{}, config.DefaultFlushInterval},
+		{&config.Params{FlushInterval: 10 * time.Second}, 10 * time.Second},
+	}
+
+	for _, entry := range table {
+		if flushInterval := config.FlushInterval(entry.params); flushInterval != entry.flushInterval {
+			t.Errorf("Flush interval failed, got: %v, want: %v.", flushInterval, entry.flushInterval)
+		}
+	}
+}
+
+func TestAdapterMetricsPrefix(t *testing.T) {
+	table := []struct {
+		params *config.Params
+		prefix string
+	}{
+		{&config.Params{}, config.DefaultAdapterMetricsPrefix},
+		{&config.Params{AdapterMetricsPrefix: "wavefront.adapter."}, "wavefront.adapter."},
+	}
+
+	for _, entry := range table {
+		if prefix := config.AdapterMetricsPrefix(entry.params); prefix != entry.prefix {
+			t.Errorf("Adapter metrics prefix failed, got: %v, want: %v.", prefix, entry.prefix)
+		}
+	}
+}
+
+func TestSpoolLimits(t *testing.T) {
+	table := []struct {
+		spool      *config.Params_Spool
+		maxSize    int64
+		maxAge     time.Duration
+		replayRate int
+	}{
+		{&config.Params_Spool{}, config.DefaultSpoolMaxSize, config.DefaultSpoolMaxAge, config.DefaultSpoolReplayRate},
+		{&config.Params_Spool{MaxSize: 1024, MaxAge: time.Minute, ReplayRate: 10}, 1024, time.Minute, 10},
+	}
+
+	for _, entry := range table {
+		if maxSize := config.SpoolMaxSize(entry.spool); maxSize != entry.maxSize {
+			t.Errorf("Spool size failed, got: %v, want: %v.", maxSize, entry.maxSize)
+		}
+		if maxAge := config.SpoolMaxAge(entry.spool); maxAge != entry.maxAge {
+			t.Errorf("Spool age failed, got: %v, want: %v.", maxAge, entry.maxAge)
+		}
+		if replayRate := config.SpoolReplayRate(entry.spool); replayRate != entry.replayRate {
+			t.Errorf("Spool replay rate failed, got: %v, want: %v.", replayRate, entry.replayRate)
+		}
+	}
+	if size := config.MaxBufferSize(&config.Params_WavefrontDirect{}); size != config.DefaultMaxBufferSize {
+		t.Errorf("Buffer size failed, got: %v, want: %v.", size, config.DefaultMaxBufferSize)
+	}
+}
+
+func TestProxyAddresses(t *testing.T) {
+	table := []struct {
+		proxy     *config.Params_WavefrontProxy
+		addresses []string
+	}{
+		{&config.Params_WavefrontProxy{}, nil},
+		{&config.Params_WavefrontProxy{Address: "proxy:2878"}, []string{"proxy:2878"}},
+		{&config.Params_WavefrontProxy{Addresses: []string{"proxy-a:2878", "proxy-b:2878"}}, []string{"proxy-a:2878", "proxy-b:2878"}},
+		{&config.Params_WavefrontProxy{Address: "proxy:2878", Addresses: []string{"", "proxy-b:2878"}}, []string{"proxy:2878", "proxy-b:2878"}},
+	}
+
+	for _, entry := range table {
+		if addresses := config.ProxyAddresses(entry.proxy); !reflect.DeepEqual(addresses, entry.addresses) {
+			t.Errorf("Proxy addresses failed, got: %v, want: %v.", addresses, entry.addresses)
+		}
+	}
+
+	proxy := &config.Params_WavefrontProxy{}
+	if maxFailures := config.ProxyMaxFailures(proxy); maxFailures != config.DefaultProxyMaxFailures {
+		t.Errorf("Proxy failures failed, got: %v, want: %v.", maxFailures, config.DefaultProxyMaxFailures)
+	}
+	if ejectionTime := config.ProxyEjectionTime(proxy); ejectionTime != config.DefaultProxyEjectionTime {
+		t.Errorf("Proxy ejection time failed, got: %v, want: %v.", ejectionTime, config.DefaultProxyEjectionTime)
+	}
+	if resolveInterval := config.ProxyResolveInterval(proxy); resolveInterval != config.DefaultProxyResolveInterval {
+		t.Errorf("Proxy resolve interval failed, got: %v, want: %v.", resolveInterval, config.DefaultProxyResolveInterval)
+	}
+}
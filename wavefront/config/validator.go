@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
+	"strings"
+	"sync"
 )
 
 var (
@@ -27,6 +30,10 @@ var (
 	InvalidDirectCredsError = errors.New("invalid server or token found in configuration")
 	// InvalidProxyCredsError is returned when invalid proxy credentials are found in the configuration.
 	InvalidProxyCredsError = errors.New("invalid proxy address found in configuration")
+	// InvalidBufferSizeError is returned when a negative direct sender buffer size is found in the configuration.
+	InvalidBufferSizeError = errors.New("negative buffer size found in configuration")
+	// InvalidDistributionPortError is returned when an invalid proxy distribution port is found in the configuration.
+	InvalidDistributionPortError = errors.New("invalid proxy distribution port found in configuration")
 	// NoCredentialsError is returned when no valid credentials are found in the configuration.
 	NoCredentialsError = errors.New("no credentials were found in the configuration")
 	// NoInstanceNameError is returned when an instance name isn't supplied for a metric.
@@ -44,12 +51,100 @@ func ValidateCredentials(cfg *Params) error {
 			return InvalidDirectCredsError
 		} else if _, err := url.ParseRequestURI(direct.Server); err != nil {
 			return err
+		} else if direct.MaxBufferSize < 0 {
+			return InvalidBufferSizeError
 		}
 	} else if proxy := cfg.GetProxy(); proxy != nil {
-		if proxy.Address == "" {
+		addresses := ProxyAddresses(proxy)
+		if len(addresses) == 0 {
 			return InvalidProxyCredsError
-		} else if _, err := net.ResolveTCPAddr("tcp", proxy.Address); err != nil {
-			return err
+		} else if proxy.DistributionPort < 0 || proxy.DistributionPort > 65535 {
+			return InvalidDistributionPortError
+		}
+		for _, address := range addresses {
+			if _, _, err := ParseProxyAddress(address); err != nil {
+				return err
+			}
+		}
+		if _, known := Params_WavefrontProxy_LoadBalancing_name[int32(proxy.LoadBalancing)]; !known {
+			return fmt.Errorf("invalid proxy load balancing %v found in configuration", proxy.LoadBalancing)
+		} else if proxy.MaxFailures < 0 {
+			return errors.New("negative proxy failures found in configuration")
+		} else if proxy.EjectionTime < 0 {
+			return errors.New("negative proxy ejection time found in configuration")
+		} else if proxy.ResolveInterval < 0 {
+			return errors.New("negative proxy resolve interval found in configuration")
+		}
+	}
+	return nil
+}
+
+// ParseProxyAddress returns the host and port of a proxy address. The host may
+// be a hostname, which isn't resolved, or an IP address, with IPv6 addresses
+// in brackets. The port may be a number or a service name.
+// Ex: wavefront-proxy:2878, [fd00::1]:2878, 192.168.99.100:wavefront
+func ParseProxyAddress(address string) (string, int, error) {
+	host, service, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, err
+	}
+	if host == "" {
+		return "", 0, fmt.Errorf("address %s: missing host in address", address)
+	}
+	port, err := net.LookupPort("tcp", service)
+	if err != nil {
+		return "", 0, err
+	}
+	if port == 0 {
+		return "", 0, fmt.Errorf("address %s: invalid port", address)
+	}
+	return host, port, nil
+}
+
+// ValidateFlushInterval validates the flush interval given a Params instance. A
+// missing flush interval is valid, and is replaced by the default one.
+func ValidateFlushInterval(cfg *Params) error {
+	if cfg.FlushInterval != 0 && cfg.FlushInterval < MinFlushInterval {
+		return fmt.Errorf("flush interval %s is less than the minimum of %s", cfg.FlushInterval, MinFlushInterval)
+	}
+	return nil
+}
+
+// ValidateSpool validates the spool given a Params instance. A missing spool
+// is valid, and disables spooling.
+func ValidateSpool(cfg *Params) error {
+	spool := cfg.Spool
+	if spool == nil {
+		return nil
+	}
+	if spool.Directory == "" {
+		return errors.New("no spool directory found in configuration")
+	}
+	if spool.MaxSize < 0 {
+		return errors.New("negative spool size found in configuration")
+	}
+	if spool.MaxAge < 0 {
+		return errors.New("negative spool age found in configuration")
+	}
+	if spool.ReplayRate < 0 {
+		return errors.New("negative spool replay rate found in configuration")
+	}
+	return nil
+}
+
+// validateTagRules validates the given tag rules, if any.
+func validateTagRules(rules *Params_TagRules) error {
+	if rules == nil {
+		return nil
+	}
+	for dimension, key := range rules.Rename {
+		if dimension == "" || key == "" {
+			return fmt.Errorf("invalid tag rename from %q to %q found in configuration", dimension, key)
+		}
+	}
+	for key := range rules.Add {
+		if key == "" {
+			return errors.New("static tag without key found in configuration")
 		}
 	}
 	return nil
@@ -60,18 +155,52 @@ func validateMetric(m *Params_MetricInfo) error {
 	if m.InstanceName == "" {
 		return NoInstanceNameError
 	}
+	if m.MaxSeries < 0 {
+		return fmt.Errorf("negative series limit found for metric %s", MetricName(m))
+	}
+	if err := validateTagRules(m.Tags); err != nil {
+		return fmt.Errorf("invalid tag rules for metric %s: %v", MetricName(m), err)
+	}
+
+	if m.Aggregation != LAST && m.Type != GAUGE {
+		return fmt.Errorf("aggregation %s found for metric %s, which isn't a gauge", m.Aggregation, MetricName(m))
+	}
+	// the values of the other metric types are integers, so durations in
+	// seconds would lose their fractional part
+	if m.DurationUnit == SECONDS && m.Type != GAUGE {
+		return fmt.Errorf("duration unit %s found for metric %s, which isn't a gauge", m.DurationUnit, MetricName(m))
+	}
 
 	switch m.Type {
 	case HISTOGRAM:
 		if m.Sample == nil || m.Sample.GetDefinition() == nil || (m.Sample.GetExpDecay() == nil && m.Sample.GetUniform() == nil) {
 			return fmt.Errorf("no sample definition was found for histogram metric %s", MetricName(m))
 		}
+	case TIMER:
+		if m.Sample != nil && m.Sample.GetExpDecay() == nil && m.Sample.GetUniform() == nil {
+			return fmt.Errorf("no sample definition was found for timer metric %s", MetricName(m))
+		}
 	}
 	return nil
 }
 
 // ValidateMetrics validates the metrics configuration given a Params instance.
 func ValidateMetrics(cfg *Params) error {
+	if cfg.MaxSeries < 0 {
+		return errors.New("negative series limit found in configuration")
+	}
+	if cfg.SeriesExpiryIntervals < 0 {
+		return errors.New("negative series expiry found in configuration")
+	}
+	if err := validateTagRules(cfg.Tags); err != nil {
+		return err
+	}
+	for dimension, format := range cfg.TagFormats {
+		if _, known := Params_TagFormat_name[int32(format)]; dimension == "" || !known {
+			return fmt.Errorf("invalid tag format %v for dimension %q found in configuration", format, dimension)
+		}
+	}
+
 	names := make(map[string]struct{})
 	instanceNames := make(map[string]struct{})
 
@@ -92,7 +221,117 @@ func ValidateMetrics(cfg *Params) error {
 			return fmt.Errorf("duplicate metrics found for instance %s", m.InstanceName)
 		}
 		instanceNames[m.InstanceName] = struct{}{}
+
+		// distributions can only be sent to a proxy listening for them
+		if proxy := cfg.GetProxy(); proxy != nil && m.Type == WAVEFRONT_HISTOGRAM && proxy.DistributionPort == 0 {
+			return fmt.Errorf("no proxy distribution port was found for wavefront histogram metric %s", name)
+		}
 	}
 
 	return nil
 }
+
+// maxCompiledRewrites is the maximum number of compiled rewrite sets kept.
+const maxCompiledRewrites = 100
+
+var (
+	// compiledRewrites caches the compiled tag rewrites by rule set, so that
+	// the rewrites of a configuration are compiled once.
+	compiledRewrites = make(map[string][]*TagRewriter)
+	// compiledRewritesMu guards compiledRewrites.
+	compiledRewritesMu sync.Mutex
+)
+
+// TagRewriter is a compiled tag rewrite.
+type TagRewriter struct {
+	// Tag is the dimension whose values are rewritten.
+	Tag         string
+	match       *regexp.Regexp
+	replacement string
+	lowercase   bool
+	maxLength   int
+}
+
+// Rewrite rewrites the given tag value. Without a match, the whole value is
+// replaced with the replacement, if any.
+func (r *TagRewriter) Rewrite(value string) string {
+	if r.match != nil {
+		value = r.match.ReplaceAllString(value, r.replacement)
+	} else if r.replacement != "" {
+		value = r.replacement
+	}
+	if r.lowercase {
+		value = strings.ToLower(value)
+	}
+	if r.maxLength > 0 {
+		if runes := []rune(value); len(runes) > r.maxLength {
+			value = string(runes[:r.maxLength])
+		}
+	}
+	return value
+}
+
+// compileTagRewrite validates and compiles a given tag rewrite.
+func compileTagRewrite(rw *Params_TagRewrite) (*TagRewriter, error) {
+	if rw.Tag == "" {
+		return nil, errors.New("tag rewrite without tag found in configuration")
+	}
+	if rw.MaxLength < 0 {
+		return nil, fmt.Errorf("negative maximum length found in the rewrite of tag %s", rw.Tag)
+	}
+	if rw.Match == "" && rw.Replacement == "" && !rw.Lowercase && rw.MaxLength == 0 {
+		return nil, fmt.Errorf("the rewrite of tag %s doesn't change anything", rw.Tag)
+	}
+
+	r := &TagRewriter{
+		Tag:         rw.Tag,
+		replacement: rw.Replacement,
+		lowercase:   rw.Lowercase,
+		maxLength:   int(rw.MaxLength),
+	}
+	if rw.Match != "" {
+		match, err := regexp.Compile(rw.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match in the rewrite of tag %s: %v", rw.Tag, err)
+		}
+		r.match = match
+	}
+	return r, nil
+}
+
+// ValidateTagRewrites validates the tag rewrites given a Params instance, and
+// returns them compiled. The rewrites of a configuration are compiled once.
+func ValidateTagRewrites(cfg *Params) ([]*TagRewriter, error) {
+	if len(cfg.TagRewrites) == 0 {
+		return nil, nil
+	}
+
+	var key strings.Builder
+	for _, rw := range cfg.TagRewrites {
+		data, err := rw.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&key, "%d:%s", len(data), data)
+	}
+
+	compiledRewritesMu.Lock()
+	defer compiledRewritesMu.Unlock()
+	if rewriters, found := compiledRewrites[key.String()]; found {
+		return rewriters, nil
+	}
+
+	rewriters := make([]*TagRewriter, 0, len(cfg.TagRewrites))
+	for _, rw := range cfg.TagRewrites {
+		r, err := compileTagRewrite(rw)
+		if err != nil {
+			return nil, err
+		}
+		rewriters = append(rewriters, r)
+	}
+	if len(compiledRewrites) >= maxCompiledRewrites {
+		compiledRewrites = make(map[string][]*TagRewriter)
+	}
+	compiledRewrites[key.String()] = rewriters
+	return rewriters, nil
+}
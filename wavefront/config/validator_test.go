@@ -18,12 +18,16 @@ package config_test
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
 )
 
 func TestValidateCredentials(t *testing.T) {
+	_, uriErr := url.ParseRequestURI("not-a-valid-uri")
+
 	table := []struct {
 		params config.Params
 		err    error
@@ -43,7 +47,7 @@ func TestValidateCredentials(t *testing.T) {
 				Server: "not-a-valid-uri",
 				Token:  "dummy-token",
 			},
-		}}, fmt.Errorf("parse not-a-valid-uri: invalid URI for request")},
+		}}, uriErr},
 		{config.Params{Credentials: &config.Params_Direct{
 			Direct: &config.Params_WavefrontDirect{Token: ""},
 		}}, config.InvalidDirectCredsError},
@@ -53,6 +57,13 @@ func TestValidateCredentials(t *testing.T) {
 				Token:  "dummy-token",
 			},
 		}}, nil},
+		{config.Params{Credentials: &config.Params_Direct{
+			Direct: &config.Params_WavefrontDirect{
+				Server:        "https://server.wavefront.com",
+				Token:         "dummy-token",
+				MaxBufferSize: -1,
+			},
+		}}, config.InvalidBufferSizeError},
 		{config.Params{Credentials: &config.Params_Proxy{
 			Proxy: &config.Params_WavefrontProxy{Address: ""},
 		}}, config.InvalidProxyCredsError},
@@ -62,6 +73,46 @@ func TestValidateCredentials(t *testing.T) {
 		{config.Params{Credentials: &config.Params_Proxy{
 			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345"},
 		}}, nil},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", DistributionPort: 40000},
+		}}, nil},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "[fd00::1]:2878"},
+		}}, nil},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "wavefront-proxy.istio-system.svc.cluster.local:2878"},
+		}}, nil},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "fd00::1:2878"},
+		}}, fmt.Errorf("address fd00::1:2878: too many colons in address")},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", DistributionPort: 70000},
+		}}, config.InvalidDistributionPortError},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Addresses: []string{"192.168.99.100:12345", "192.168.99.101:12345"}},
+		}}, nil},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Addresses: []string{""}},
+		}}, config.InvalidProxyCredsError},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", Addresses: []string{"not-a-valid-address"}},
+		}}, fmt.Errorf("address not-a-valid-address: missing port in address")},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", LoadBalancing: config.CONSISTENT_HASH,
+				MaxFailures: 5, EjectionTime: time.Minute},
+		}}, nil},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", LoadBalancing: 7},
+		}}, fmt.Errorf("invalid proxy load balancing 7 found in configuration")},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", MaxFailures: -1},
+		}}, fmt.Errorf("negative proxy failures found in configuration")},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", EjectionTime: -time.Second},
+		}}, fmt.Errorf("negative proxy ejection time found in configuration")},
+		{config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:12345", ResolveInterval: -time.Second},
+		}}, fmt.Errorf("negative proxy resolve interval found in configuration")},
 	}
 
 	for _, entry := range table {
@@ -71,6 +122,80 @@ func TestValidateCredentials(t *testing.T) {
 	}
 }
 
+func TestParseProxyAddress(t *testing.T) {
+	table := []struct {
+		address string
+		host    string
+		port    int
+		err     error
+	}{
+		{"192.168.99.100:2878", "192.168.99.100", 2878, nil},
+		{"[fd00::1]:2878", "fd00::1", 2878, nil},
+		{"[fe80::1%eth0]:2878", "fe80::1%eth0", 2878, nil},
+		{"wavefront-proxy:2878", "wavefront-proxy", 2878, nil},
+		{"wavefront-proxy:http", "wavefront-proxy", 80, nil},
+		{"wavefront-proxy", "", 0, errors.New("address wavefront-proxy: missing port in address")},
+		{":2878", "", 0, errors.New("address :2878: missing host in address")},
+		{"fd00::1:2878", "", 0, errors.New("address fd00::1:2878: too many colons in address")},
+		{"wavefront-proxy:0", "", 0, errors.New("address wavefront-proxy:0: invalid port")},
+		{"wavefront-proxy:70000", "", 0, errors.New("address 70000: invalid port")},
+	}
+
+	for _, entry := range table {
+		host, port, err := config.ParseProxyAddress(entry.address)
+		if host != entry.host || port != entry.port || fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Parsing failed for %s, got: %v, %v, %v, want: %v, %v, %v.",
+				entry.address, host, port, err, entry.host, entry.port, entry.err)
+		}
+	}
+
+	if _, _, err := config.ParseProxyAddress("wavefront-proxy:no-such-service"); err == nil {
+		t.Errorf("Expected an unknown service to be rejected.")
+	}
+}
+
+func TestValidateFlushInterval(t *testing.T) {
+	table := []struct {
+		flushInterval time.Duration
+		err           error
+	}{
+		{0, nil},
+		{time.Second, nil},
+		{time.Minute, nil},
+		{500 * time.Millisecond, errors.New("flush interval 500ms is less than the minimum of 1s")},
+		{-time.Second, errors.New("flush interval -1s is less than the minimum of 1s")},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{FlushInterval: entry.flushInterval}
+		if err := config.ValidateFlushInterval(cfg); fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.flushInterval, err, entry.err)
+		}
+	}
+}
+
+func TestValidateSpool(t *testing.T) {
+	table := []struct {
+		spool *config.Params_Spool
+		err   error
+	}{
+		{nil, nil},
+		{&config.Params_Spool{Directory: "/var/spool/wavefront"}, nil},
+		{&config.Params_Spool{Directory: "/var/spool/wavefront", MaxSize: 1 << 20, MaxAge: time.Hour, ReplayRate: 100}, nil},
+		{&config.Params_Spool{}, errors.New("no spool directory found in configuration")},
+		{&config.Params_Spool{Directory: "/var/spool/wavefront", MaxSize: -1}, errors.New("negative spool size found in configuration")},
+		{&config.Params_Spool{Directory: "/var/spool/wavefront", MaxAge: -time.Hour}, errors.New("negative spool age found in configuration")},
+		{&config.Params_Spool{Directory: "/var/spool/wavefront", ReplayRate: -1}, errors.New("negative spool replay rate found in configuration")},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{Spool: entry.spool}
+		if err := config.ValidateSpool(cfg); fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.spool, err, entry.err)
+		}
+	}
+}
+
 func TestValidateMetrics(t *testing.T) {
 	err := fmt.Errorf("no sample definition was found for histogram metric metric-name")
 
@@ -124,6 +249,78 @@ func TestValidateMetrics(t *testing.T) {
 				},
 			},
 		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.WAVEFRONT_HISTOGRAM,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.WAVEFRONT_HISTOGRAM,
+			Granularity:  config.HOUR,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.TIMER,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.TIMER,
+			Sample:       &config.Params_MetricInfo_Sample{},
+		}, errors.New("no sample definition was found for timer metric metric-name")},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.METER,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.GAUGE,
+			Aggregation:  config.MEAN,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.COUNTER,
+			Aggregation:  config.SUM,
+		}, errors.New("aggregation SUM found for metric metric-name, which isn't a gauge")},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.GAUGE,
+			DurationUnit: config.SECONDS,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.TIMER,
+			DurationUnit: config.MILLISECONDS,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.TIMER,
+			DurationUnit: config.SECONDS,
+		}, errors.New("duration unit SECONDS found for metric metric-name, which isn't a gauge")},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.GAUGE,
+			MaxSeries:    100,
+			Overflow:     config.COLLAPSE,
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.GAUGE,
+			MaxSeries:    -1,
+		}, errors.New("negative series limit found for metric metric-name")},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.GAUGE,
+			Tags: &config.Params_TagRules{
+				Exclude: []string{"source_version"},
+				Rename:  map[string]string{"destination_service": "service"},
+				Add:     map[string]string{"cluster": "prod-us-east"},
+			},
+		}, nil},
+		{config.Params_MetricInfo{
+			InstanceName: "metric-name",
+			Type:         config.GAUGE,
+			Tags:         &config.Params_TagRules{Rename: map[string]string{"destination_service": ""}},
+		}, errors.New(`invalid tag rules for metric metric-name: invalid tag rename from "destination_service" to "" found in configuration`)},
 	}
 
 	for _, entry := range table {
@@ -134,6 +331,38 @@ func TestValidateMetrics(t *testing.T) {
 	}
 }
 
+func TestValidateMetricsDistributionPort(t *testing.T) {
+	proxy := func(distributionPort int32) *config.Params_Proxy {
+		return &config.Params_Proxy{Proxy: &config.Params_WavefrontProxy{
+			Address:          "192.168.99.100:2878",
+			DistributionPort: distributionPort,
+		}}
+	}
+	direct := &config.Params_Direct{Direct: &config.Params_WavefrontDirect{
+		Server: "https://server.wavefront.com",
+		Token:  "dummy-token",
+	}}
+	histogram := &config.Params_MetricInfo{InstanceName: "histogram", Type: config.WAVEFRONT_HISTOGRAM}
+	gauge := &config.Params_MetricInfo{InstanceName: "gauge", Type: config.GAUGE}
+
+	table := []struct {
+		params config.Params
+		err    error
+	}{
+		{config.Params{Credentials: direct, Metrics: []*config.Params_MetricInfo{histogram}}, nil},
+		{config.Params{Credentials: proxy(40000), Metrics: []*config.Params_MetricInfo{histogram}}, nil},
+		{config.Params{Credentials: proxy(0), Metrics: []*config.Params_MetricInfo{gauge}}, nil},
+		{config.Params{Credentials: proxy(0), Metrics: []*config.Params_MetricInfo{histogram}},
+			errors.New("no proxy distribution port was found for wavefront histogram metric histogram")},
+	}
+
+	for _, entry := range table {
+		if err := config.ValidateMetrics(&entry.params); fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.params, err, entry.err)
+		}
+	}
+}
+
 func TestValidateMetricsDuplicate(t *testing.T) {
 	gaugeErr := errors.New("duplicate metric gauge found, please supply or change the metric name")
 	table := []struct {
@@ -184,3 +413,142 @@ func TestValidateMetricsDuplicate(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateMetricsMaxSeries(t *testing.T) {
+	table := []struct {
+		maxSeries int32
+		err       error
+	}{
+		{0, nil},
+		{1000, nil},
+		{-1, errors.New("negative series limit found in configuration")},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{MaxSeries: entry.maxSeries}
+		if err := config.ValidateMetrics(cfg); fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.maxSeries, err, entry.err)
+		}
+	}
+}
+
+func TestValidateMetricsTagRules(t *testing.T) {
+	table := []struct {
+		tags *config.Params_TagRules
+		err  error
+	}{
+		{nil, nil},
+		{&config.Params_TagRules{Add: map[string]string{"cluster": "prod-us-east"}}, nil},
+		{&config.Params_TagRules{Add: map[string]string{"": "prod-us-east"}},
+			errors.New("static tag without key found in configuration")},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{Tags: entry.tags}
+		if err := config.ValidateMetrics(cfg); fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.tags, err, entry.err)
+		}
+	}
+}
+
+func TestValidateTagRewrites(t *testing.T) {
+	table := []struct {
+		rewrite config.Params_TagRewrite
+		in      string
+		out     string
+		err     error
+	}{
+		{config.Params_TagRewrite{Tag: "request_path", Match: "/users/[0-9]+/", Replacement: "/users/{id}/"},
+			"/users/12345/orders", "/users/{id}/orders", nil},
+		{config.Params_TagRewrite{Tag: "request_path", Match: "^/(?P<api>[a-z]+)/.*$", Replacement: "/${api}/*"},
+			"/api/v1/items", "/api/*", nil},
+		{config.Params_TagRewrite{Tag: "destination_service", Lowercase: true, MaxLength: 7},
+			"Reviews.Default.svc", "reviews", nil},
+		{config.Params_TagRewrite{Tag: "request_path", Replacement: "redacted"},
+			"/users/12345/orders", "redacted", nil},
+		{config.Params_TagRewrite{Tag: "request_path", Replacement: "Redacted-Path", Lowercase: true, MaxLength: 8},
+			"/users/12345/orders", "redacted", nil},
+		{config.Params_TagRewrite{Match: "[0-9]+"}, "", "",
+			errors.New("tag rewrite without tag found in configuration")},
+		{config.Params_TagRewrite{Tag: "request_path", Match: "[0-9+"}, "", "",
+			errors.New("invalid match in the rewrite of tag request_path: error parsing regexp: missing closing ]: `[0-9+`")},
+		{config.Params_TagRewrite{Tag: "request_path", MaxLength: -1}, "", "",
+			errors.New("negative maximum length found in the rewrite of tag request_path")},
+		{config.Params_TagRewrite{Tag: "request_path"}, "", "",
+			errors.New("the rewrite of tag request_path doesn't change anything")},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{TagRewrites: []*config.Params_TagRewrite{&entry.rewrite}}
+		rewriters, err := config.ValidateTagRewrites(cfg)
+		if fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.rewrite, err, entry.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if out := rewriters[0].Rewrite(entry.in); out != entry.out {
+			t.Errorf("Rewrite failed for %v, got: %q, want: %q.", entry.rewrite, out, entry.out)
+		}
+	}
+}
+
+func TestValidateTagRewritesCache(t *testing.T) {
+	cfg := func() *config.Params {
+		return &config.Params{TagRewrites: []*config.Params_TagRewrite{
+			{Tag: "request_path", Match: "[0-9]+", Replacement: "{id}"},
+		}}
+	}
+
+	first, err := config.ValidateTagRewrites(cfg())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := config.ValidateTagRewrites(cfg())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first[0] != second[0] {
+		t.Errorf("Expected the rewrites of an identical configuration to be compiled once.")
+	}
+}
+
+func TestValidateMetricsTagFormats(t *testing.T) {
+	table := []struct {
+		formats map[string]config.Params_TagFormat
+		err     error
+	}{
+		{nil, nil},
+		{map[string]config.Params_TagFormat{"request_time": config.EPOCH_SECONDS, "source_ip": config.COLON_NOTATION}, nil},
+		{map[string]config.Params_TagFormat{"": config.EPOCH_SECONDS},
+			errors.New(`invalid tag format EPOCH_SECONDS for dimension "" found in configuration`)},
+		{map[string]config.Params_TagFormat{"request_time": 42},
+			errors.New(`invalid tag format 42 for dimension "request_time" found in configuration`)},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{TagFormats: entry.formats}
+		if err := config.ValidateMetrics(cfg); fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.formats, err, entry.err)
+		}
+	}
+}
+
+func TestValidateMetricsSeriesExpiry(t *testing.T) {
+	table := []struct {
+		intervals int32
+		err       error
+	}{
+		{0, nil},
+		{12, nil},
+		{-1, errors.New("negative series expiry found in configuration")},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{SeriesExpiryIntervals: entry.intervals}
+		if err := config.ValidateMetrics(cfg); fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Validation failed for %v, got: %v, want: %v.", entry.intervals, err, entry.err)
+		}
+	}
+}
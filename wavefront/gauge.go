@@ -0,0 +1,98 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"math"
+	"sync"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+)
+
+// aggregatingGauge is a gauge reporting an aggregate of the values it was
+// updated with since it was last reported, that is over each flush interval.
+// If it wasn't updated, it reports the previous aggregate, except for sums
+// that are 0.
+type aggregatingGauge struct {
+	aggregation config.Params_MetricInfo_Aggregation
+	mu          sync.Mutex
+	value       float64
+	sum         float64
+	count       int64
+}
+
+// ensure that aggregatingGauge is reported as a gauge.
+var _ metrics.GaugeFloat64 = &aggregatingGauge{}
+
+// newAggregatingGauge creates a gauge aggregating its values as given.
+func newAggregatingGauge(aggregation config.Params_MetricInfo_Aggregation) *aggregatingGauge {
+	return &aggregatingGauge{aggregation: aggregation}
+}
+
+// Update records a value.
+func (g *aggregatingGauge) Update(v float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.count++
+	g.sum += v
+	if g.count == 1 {
+		g.value = v
+		return
+	}
+	switch g.aggregation {
+	case config.MAX:
+		g.value = math.Max(g.value, v)
+	case config.MIN:
+		g.value = math.Min(g.value, v)
+	case config.LAST:
+		g.value = v
+	}
+}
+
+// aggregate returns the aggregate of the values recorded in the interval.
+func (g *aggregatingGauge) aggregate() float64 {
+	switch {
+	case g.aggregation == config.SUM:
+		return g.sum
+	case g.aggregation == config.MEAN && g.count > 0:
+		return g.sum / float64(g.count)
+	default:
+		return g.value
+	}
+}
+
+// Value returns the aggregate of the values recorded since it was last
+// called, and starts a new interval. It's called when the gauge is reported.
+func (g *aggregatingGauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	value := g.aggregate()
+	g.value = value
+	g.sum = 0
+	g.count = 0
+	return value
+}
+
+// Snapshot returns a read-only copy of the current aggregate, without
+// starting a new interval.
+func (g *aggregatingGauge) Snapshot() metrics.GaugeFloat64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return metrics.GaugeFloat64Snapshot(g.aggregate())
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"testing"
+
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+)
+
+func TestAggregatingGauge(t *testing.T) {
+	table := []struct {
+		aggregation config.Params_MetricInfo_Aggregation
+		// the values reported after each interval, the second one being empty
+		values []float64
+	}{
+		{config.LAST, []float64{2, 2, 6}},
+		{config.MAX, []float64{4, 4, 6}},
+		{config.MIN, []float64{1, 1, 5}},
+		{config.SUM, []float64{7, 0, 11}},
+		{config.MEAN, []float64{7.0 / 3, 7.0 / 3, 5.5}},
+	}
+	intervals := [][]float64{{1, 4, 2}, {}, {5, 6}}
+
+	for _, entry := range table {
+		g := newAggregatingGauge(entry.aggregation)
+		for i, interval := range intervals {
+			for _, v := range interval {
+				g.Update(v)
+			}
+			if snapshot := g.Snapshot().Value(); snapshot != entry.values[i] {
+				t.Errorf("Snapshot %d failed for %v, got: %v, want: %v.", i, entry.aggregation, snapshot, entry.values[i])
+			}
+			if value := g.Value(); value != entry.values[i] {
+				t.Errorf("Interval %d failed for %v, got: %v, want: %v.", i, entry.aggregation, value, entry.values[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"istio.io/istio/pkg/log"
+)
+
+// httpServer serves HTTP endpoints of the adapter next to its gRPC server.
+type httpServer struct {
+	name     string
+	listener net.Listener
+	server   *http.Server
+}
+
+// newHTTPServer creates an HTTP server listening at the given address.
+func newHTTPServer(name, addr string, handler http.Handler) (*httpServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen on %s socket: %v", name, err)
+	}
+	return &httpServer{
+		name:     name,
+		listener: listener,
+		server:   &http.Server{Handler: handler},
+	}, nil
+}
+
+// run serves the endpoints until the server is closed.
+func (hs *httpServer) run() {
+	if err := hs.server.Serve(hs.listener); err != nil && err != http.ErrServerClosed {
+		log.Errorf("%s server stopped: %v", hs.name, err)
+	}
+}
+
+// close stops serving the endpoints.
+func (hs *httpServer) close() {
+	_ = hs.server.Close()
+}
@@ -0,0 +1,177 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+)
+
+// prometheusNamespace prefixes the names of the metrics exported to Prometheus.
+const prometheusNamespace = "wavefront_adapter_"
+
+// summaryQuantiles are the quantiles exported for timers and histograms.
+var summaryQuantiles = []float64{0.5, 0.75, 0.95, 0.99}
+
+type (
+	// promSample is a sample of a Prometheus metric family.
+	promSample struct {
+		suffix string
+		labels map[string]string
+		value  float64
+	}
+
+	// promFamily is a Prometheus metric family, with all the samples of a
+	// metric across its label values.
+	promFamily struct {
+		kind    string
+		samples []promSample
+	}
+
+	// promFamilies collects the metric families to export, by name.
+	promFamilies map[string]*promFamily
+)
+
+// newMetricsHandler returns the handler of the /metrics endpoint, exporting
+// the metrics about the adapter itself in the Prometheus text format. The
+// metrics are read from the adapter registries rather than from the reporters,
+// so they are available even when no reporter could be created.
+func newMetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePrometheus(w, systemStats, pipelineStats)
+	})
+	return mux
+}
+
+// writePrometheus writes the metrics of the given registries in the Prometheus
+// text format. The registry keys are decoded into names and labels.
+func writePrometheus(w io.Writer, registries ...metrics.Registry) {
+	families := make(promFamilies)
+	for _, registry := range registries {
+		registry.Each(func(key string, metric interface{}) {
+			name, tags := wf.DecodeKey(key)
+			families.add(prometheusName(name), tags, metric)
+		})
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		family := families[name]
+		lines := make([]string, 0, len(family.samples))
+		for _, sample := range family.samples {
+			lines = append(lines, fmt.Sprintf("%s%s%s %s\n", name, sample.suffix,
+				prometheusLabels(sample.labels), strconv.FormatFloat(sample.value, 'g', -1, 64)))
+		}
+		sort.Strings(lines)
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, family.kind)
+		for _, line := range lines {
+			io.WriteString(w, line)
+		}
+	}
+}
+
+// add adds the samples of the given metric to the family with the given name.
+// Metrics of unsupported types are skipped.
+func (pf promFamilies) add(name string, labels map[string]string, metric interface{}) {
+	switch m := metric.(type) {
+	case metrics.Counter:
+		pf.sample(name+"_total", "counter", "", labels, float64(m.Count()))
+	case metrics.Gauge:
+		pf.sample(name, "gauge", "", labels, float64(m.Value()))
+	case metrics.GaugeFloat64:
+		pf.sample(name, "gauge", "", labels, m.Value())
+	case metrics.Meter:
+		pf.sample(name+"_total", "counter", "", labels, float64(m.Count()))
+	case metrics.Timer:
+		// timers record nanoseconds, exported in seconds
+		t := m.Snapshot()
+		pf.summary(name+"_seconds", labels, t.Percentiles(summaryQuantiles), float64(t.Sum()), t.Count(), float64(time.Second))
+	case metrics.Histogram:
+		h := m.Snapshot()
+		pf.summary(name, labels, h.Percentiles(summaryQuantiles), float64(h.Sum()), h.Count(), 1)
+	}
+}
+
+// sample adds a sample to the family with the given name and type.
+func (pf promFamilies) sample(name, kind, suffix string, labels map[string]string, value float64) {
+	family, ok := pf[name]
+	if !ok {
+		family = &promFamily{kind: kind}
+		pf[name] = family
+	}
+	family.samples = append(family.samples, promSample{suffix: suffix, labels: labels, value: value})
+}
+
+// summary adds the samples of a summary, dividing its values by the given scale.
+func (pf promFamilies) summary(name string, labels map[string]string, quantiles []float64, sum float64, count int64, scale float64) {
+	for i, q := range summaryQuantiles {
+		ql := map[string]string{"quantile": strconv.FormatFloat(q, 'g', -1, 64)}
+		for k, v := range labels {
+			ql[k] = v
+		}
+		pf.sample(name, "summary", "", ql, quantiles[i]/scale)
+	}
+	pf.sample(name, "summary", "_sum", labels, sum/scale)
+	pf.sample(name, "summary", "_count", labels, float64(count))
+}
+
+// prometheusName converts a metric or label name to a valid Prometheus name,
+// replacing the invalid characters, such as dots, with underscores.
+func prometheusName(name string) string {
+	return prometheusNamespace + sanitizePrometheus(name)
+}
+
+// sanitizePrometheus replaces the characters that aren't valid in Prometheus
+// names with underscores.
+func sanitizePrometheus(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// labelEscaper escapes Prometheus label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// prometheusLabels formats the given labels, sorted by name.
+func prometheusLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", sanitizePrometheus(k), labelEscaper.Replace(v)))
+	}
+	sort.Strings(pairs)
+	return "{" + strings.Join(pairs, ",") + "}"
+}
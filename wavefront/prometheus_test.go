@@ -0,0 +1,81 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.GetOrRegisterGaugeFloat64("cpu.user", registry).Update(0.5)
+	metrics.GetOrRegisterCounter(wf.EncodeKey("instances.dropped", map[string]string{"handler": "1234", "reason": "unknown_instance"}), registry).Inc(2)
+	metrics.GetOrRegisterCounter(wf.EncodeKey("instances.dropped", map[string]string{"handler": "5678", "reason": "say \"hi\""}), registry).Inc(1)
+	metrics.GetOrRegisterTimer(wf.EncodeKey("requests.duration", map[string]string{"handler": "1234"}), registry).Update(2 * time.Second)
+	_ = registry.Register("sender.queue.size.approx", metrics.NewFunctionalGauge(func() int64 { return 7 }))
+
+	var buf bytes.Buffer
+	writePrometheus(&buf, registry)
+
+	expected := strings.Join([]string{
+		"# TYPE wavefront_adapter_cpu_user gauge",
+		"wavefront_adapter_cpu_user 0.5",
+		"# TYPE wavefront_adapter_instances_dropped_total counter",
+		`wavefront_adapter_instances_dropped_total{handler="1234",reason="unknown_instance"} 2`,
+		`wavefront_adapter_instances_dropped_total{handler="5678",reason="say \"hi\""} 1`,
+		"# TYPE wavefront_adapter_requests_duration_seconds summary",
+		`wavefront_adapter_requests_duration_seconds_count{handler="1234"} 1`,
+		`wavefront_adapter_requests_duration_seconds_sum{handler="1234"} 2`,
+		`wavefront_adapter_requests_duration_seconds{handler="1234",quantile="0.5"} 2`,
+		`wavefront_adapter_requests_duration_seconds{handler="1234",quantile="0.75"} 2`,
+		`wavefront_adapter_requests_duration_seconds{handler="1234",quantile="0.95"} 2`,
+		`wavefront_adapter_requests_duration_seconds{handler="1234",quantile="0.99"} 2`,
+		"# TYPE wavefront_adapter_sender_queue_size_approx gauge",
+		"wavefront_adapter_sender_queue_size_approx 7",
+		"",
+	}, "\n")
+	if got := buf.String(); got != expected {
+		t.Errorf("Unexpected Prometheus output, got:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	s, err := NewWavefrontAdapter("127.0.0.1:0", WithMetricsAddress("127.0.0.1:0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	go s.Run(make(chan error, 1))
+
+	// no reporter was ever created, yet the system stats are exported
+	resp, err := http.Get("http://" + s.(*WavefrontAdapter).metrics.listener.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "# TYPE wavefront_adapter_memory_alloc gauge\n") {
+		t.Errorf("Expected the system stats to be exported, got:\n%s", body)
+	}
+}
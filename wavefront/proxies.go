@@ -0,0 +1,476 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	"github.com/wavefronthq/wavefront-sdk-go/event"
+	"github.com/wavefronthq/wavefront-sdk-go/histogram"
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+	"istio.io/istio/pkg/log"
+)
+
+// minResolveInterval is the minimum delay between the resolutions triggered
+// by failing proxies.
+const minResolveInterval = time.Second
+
+type (
+	// proxyEndpoint is the host and port of a Wavefront proxy.
+	proxyEndpoint struct {
+		host string
+		port int
+	}
+
+	// proxyResolver returns the endpoints of the proxies to send to.
+	proxyResolver func() ([]proxyEndpoint, error)
+
+	// proxyFactory creates a sender for the proxy at the given endpoint.
+	proxyFactory func(endpoint proxyEndpoint) (senders.Sender, error)
+
+	// pooledProxy is a proxy of a pool, along with its health.
+	pooledProxy struct {
+		senders.Sender
+		endpoint proxyEndpoint
+		// failures is the number of consecutive errors of the proxy
+		failures int
+		// ejectedUntil is when an ejected proxy is tried again
+		ejectedUntil time.Time
+		// lastErr is the last error of the proxy
+		lastErr error
+	}
+
+	// proxyPool is a sender spreading the data over several Wavefront proxies.
+	// A proxy is ejected after too many consecutive errors, and its data goes
+	// to the other proxies until it's tried again, once its ejection expires.
+	// The proxies are resolved again periodically, and whenever one fails, so
+	// that the pool follows the proxies whose IPs change.
+	proxyPool struct {
+		balancing    config.Params_WavefrontProxy_LoadBalancing
+		maxFailures  int
+		ejectionTime time.Duration
+		next         uint64
+		resolve      proxyResolver
+		create       proxyFactory
+		resolveNow   chan struct{}
+		done         chan struct{}
+
+		mu       sync.Mutex
+		proxies  []*pooledProxy
+		closed   bool
+		resolved time.Time
+	}
+)
+
+// String returns the address of the endpoint.
+func (e proxyEndpoint) String() string {
+	return net.JoinHostPort(e.host, strconv.Itoa(e.port))
+}
+
+// dialHost returns the host of the endpoint as given to the SDK, which appends
+// the port to it, so IPv6 addresses are bracketed.
+func (e proxyEndpoint) dialHost() string {
+	if strings.Contains(e.host, ":") {
+		return "[" + e.host + "]"
+	}
+	return e.host
+}
+
+// resolveProxyEndpoints returns the endpoints of the proxies at the given
+// addresses, which may have IPv6 hosts and named ports. An address whose host
+// resolves to several IPs, e.g. a headless service, is an endpoint per IP.
+func resolveProxyEndpoints(addresses []string) ([]proxyEndpoint, error) {
+	var endpoints []proxyEndpoint
+	seen := make(map[proxyEndpoint]bool)
+	for _, address := range addresses {
+		host, port, err := config.ParseProxyAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address: %v", err)
+		}
+		ips, err := net.LookupHost(host)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve proxy address: %v", err)
+		}
+		sort.Strings(ips)
+		for _, ip := range ips {
+			endpoint := proxyEndpoint{host: ip, port: port}
+			if !seen[endpoint] {
+				seen[endpoint] = true
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// newProxyPool resolves the proxies with resolve, creates a sender per proxy
+// with create, and returns the pool spreading the data over them.
+func newProxyPool(proxy *config.Params_WavefrontProxy, resolve proxyResolver, create proxyFactory) (*proxyPool, error) {
+	endpoints, err := resolve()
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("no wavefront proxy found")
+	}
+	p := &proxyPool{
+		balancing:    proxy.LoadBalancing,
+		maxFailures:  config.ProxyMaxFailures(proxy),
+		ejectionTime: config.ProxyEjectionTime(proxy),
+		resolve:      resolve,
+		create:       create,
+		resolveNow:   make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		resolved:     time.Now(),
+	}
+	for _, endpoint := range endpoints {
+		sender, err := create(endpoint)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.proxies = append(p.proxies, &pooledProxy{Sender: sender, endpoint: endpoint})
+	}
+	go p.watch(config.ProxyResolveInterval(proxy))
+	return p, nil
+}
+
+// watch resolves the proxies again every resolve interval, and when asked to,
+// until the pool is closed.
+func (p *proxyPool) watch(resolveInterval time.Duration) {
+	ticker := time.NewTicker(resolveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.resolveNow:
+		case <-p.done:
+			return
+		}
+		p.refresh()
+	}
+}
+
+// requestRefresh asks for the proxies to be resolved again, unless they were
+// resolved less than minResolveInterval ago.
+func (p *proxyPool) requestRefresh() {
+	p.mu.Lock()
+	recent := time.Since(p.resolved) < minResolveInterval
+	p.mu.Unlock()
+	if recent {
+		return
+	}
+	select {
+	case p.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+// refresh resolves the proxies again. If they changed, senders are created
+// for the new proxies, and the senders of the proxies that are gone are
+// closed. The proxies that didn't change keep their sender and health.
+func (p *proxyPool) refresh() {
+	endpoints, err := p.resolve()
+	p.mu.Lock()
+	p.resolved = time.Now()
+	p.mu.Unlock()
+	if err != nil || len(endpoints) == 0 {
+		log.Warnf("couldn't resolve the wavefront proxies again, keeping the previous ones: %v", err)
+		return
+	}
+
+	current := make(map[proxyEndpoint]*pooledProxy)
+	for _, pp := range p.members() {
+		current[pp.endpoint] = pp
+	}
+	var proxies, created []*pooledProxy
+	for _, endpoint := range endpoints {
+		if pp, found := current[endpoint]; found {
+			proxies = append(proxies, pp)
+			delete(current, endpoint)
+			continue
+		}
+		sender, err := p.create(endpoint)
+		if err != nil {
+			log.Warnf("couldn't create sender for wavefront proxy %s: %v", endpoint, err)
+			continue
+		}
+		pp := &pooledProxy{Sender: sender, endpoint: endpoint}
+		proxies = append(proxies, pp)
+		created = append(created, pp)
+	}
+	if len(created) == 0 && len(current) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	if p.closed || len(proxies) == 0 {
+		p.mu.Unlock()
+		for _, pp := range created {
+			pp.Close()
+		}
+		return
+	}
+	p.proxies = proxies
+	p.mu.Unlock()
+
+	for _, pp := range created {
+		log.Infof("added wavefront proxy %s", pp.endpoint)
+	}
+	for _, pp := range current {
+		log.Infof("removed wavefront proxy %s", pp.endpoint)
+		pp.Close()
+	}
+}
+
+// healthy returns whether the proxy isn't ejected at the given time. A proxy
+// whose ejection expired is tried again, but ejected again after an error.
+func (pp *pooledProxy) healthy(maxFailures int, now time.Time) bool {
+	return pp.failures < maxFailures || !now.Before(pp.ejectedUntil)
+}
+
+// seriesKey returns the key identifying a series, hashed to pick its proxy.
+func seriesKey(name, source string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte(0)
+	sb.WriteString(source)
+	for _, key := range keys {
+		sb.WriteByte(0)
+		sb.WriteString(key)
+		sb.WriteByte('=')
+		sb.WriteString(tags[key])
+	}
+	return sb.String()
+}
+
+// hashScore returns the score of a proxy for a series key. The series goes to
+// the healthy proxy with the highest score, so that only the series of a proxy
+// that's ejected or removed move to other proxies.
+func hashScore(key string, endpoint proxyEndpoint) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(endpoint.String()))
+	return h.Sum64()
+}
+
+// candidates returns the proxies to send a series to, in order of preference.
+// Ejected proxies are only returned when all of them are ejected.
+func (p *proxyPool) candidates(key string) []*pooledProxy {
+	now := time.Now()
+	p.mu.Lock()
+	var healthy []*pooledProxy
+	for _, pp := range p.proxies {
+		if pp.healthy(p.maxFailures, now) {
+			healthy = append(healthy, pp)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = append(healthy, p.proxies...)
+	}
+	p.mu.Unlock()
+
+	if len(healthy) < 2 {
+		return healthy
+	}
+	switch p.balancing {
+	case config.CONSISTENT_HASH:
+		scores := make(map[*pooledProxy]uint64, len(healthy))
+		for _, pp := range healthy {
+			scores[pp] = hashScore(key, pp.endpoint)
+		}
+		sort.Slice(healthy, func(i, j int) bool {
+			return scores[healthy[i]] > scores[healthy[j]]
+		})
+	default:
+		start := int(atomic.AddUint64(&p.next, 1) % uint64(len(healthy)))
+		healthy = append(healthy[start:], healthy[:start]...)
+	}
+	return healthy
+}
+
+// record records the outcome of sending data to, or flushing, a proxy. Only
+// a successful send clears its failures: a proxy sender whose connection was
+// reset after an error has nothing to flush, so its flushes succeed.
+func (p *proxyPool) record(pp *pooledProxy, err error) {
+	if err != nil {
+		// the proxy may have moved
+		p.requestRefresh()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		if pp.failures >= p.maxFailures {
+			log.Infof("wavefront proxy %s recovered", pp.endpoint)
+		}
+		pp.failures = 0
+		return
+	}
+	pp.failures++
+	pp.lastErr = err
+	if pp.failures >= p.maxFailures {
+		now := time.Now()
+		if pp.failures == p.maxFailures || !now.Before(pp.ejectedUntil) {
+			log.Warnf("ejecting wavefront proxy %s for %s after %d consecutive errors: %v", pp.endpoint, p.ejectionTime, pp.failures, err)
+		}
+		pp.ejectedUntil = now.Add(p.ejectionTime)
+	}
+}
+
+// send sends data of the series with the given key with send, failing over to
+// the next proxies if a proxy returns an error.
+func (p *proxyPool) send(key string, send func(sender senders.Sender) error) error {
+	var err error
+	for _, pp := range p.candidates(key) {
+		err = send(pp.Sender)
+		p.record(pp, err)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// SendMetric sends a metric to a proxy.
+func (p *proxyPool) SendMetric(name string, value float64, ts int64, source string, tags map[string]string) error {
+	return p.send(seriesKey(name, source, tags), func(sender senders.Sender) error {
+		return sender.SendMetric(name, value, ts, source, tags)
+	})
+}
+
+// SendDeltaCounter sends a delta counter to a proxy.
+func (p *proxyPool) SendDeltaCounter(name string, value float64, source string, tags map[string]string) error {
+	return p.send(seriesKey(name, source, tags), func(sender senders.Sender) error {
+		return sender.SendDeltaCounter(name, value, source, tags)
+	})
+}
+
+// SendDistribution sends a distribution to a proxy.
+func (p *proxyPool) SendDistribution(name string, centroids []histogram.Centroid, hgs map[histogram.Granularity]bool, ts int64, source string, tags map[string]string) error {
+	return p.send(seriesKey(name, source, tags), func(sender senders.Sender) error {
+		return sender.SendDistribution(name, centroids, hgs, ts, source, tags)
+	})
+}
+
+// SendSpan sends a span to a proxy.
+func (p *proxyPool) SendSpan(name string, startMillis, durationMillis int64, source, traceID, spanID string, parents, followsFrom []string, tags []senders.SpanTag, spanLogs []senders.SpanLog) error {
+	return p.send(traceID, func(sender senders.Sender) error {
+		return sender.SendSpan(name, startMillis, durationMillis, source, traceID, spanID, parents, followsFrom, tags, spanLogs)
+	})
+}
+
+// SendEvent sends an event to a proxy.
+func (p *proxyPool) SendEvent(name string, startMillis, endMillis int64, source string, tags map[string]string, setters ...event.Option) error {
+	return p.send(seriesKey(name, source, tags), func(sender senders.Sender) error {
+		return sender.SendEvent(name, startMillis, endMillis, source, tags, setters...)
+	})
+}
+
+// members returns the proxies of the pool.
+func (p *proxyPool) members() []*pooledProxy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*pooledProxy(nil), p.proxies...)
+}
+
+// Start does nothing, the proxy senders start flushing once created.
+func (p *proxyPool) Start() {}
+
+// Flush flushes all the proxies. It fails if all of them failed, or if all
+// of them are ejected, so that a pool whose proxies are down isn't reported
+// as flushing.
+func (p *proxyPool) Flush() error {
+	var errs []string
+	proxies := p.members()
+	for _, pp := range proxies {
+		if err := pp.Flush(); err != nil {
+			p.record(pp, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", pp.endpoint, err))
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(proxies) {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	errs = nil
+	for _, pp := range p.proxies {
+		if pp.healthy(p.maxFailures, now) {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", pp.endpoint, pp.lastErr))
+	}
+	return fmt.Errorf("all the wavefront proxies are ejected: %s", strings.Join(errs, "; "))
+}
+
+// GetFailureCount returns the number of failures of all the proxies.
+func (p *proxyPool) GetFailureCount() int64 {
+	var failures int64
+	for _, pp := range p.members() {
+		failures += pp.GetFailureCount()
+	}
+	return failures
+}
+
+// Close stops resolving the proxies, and closes them.
+func (p *proxyPool) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.done)
+	}
+	p.mu.Unlock()
+
+	for _, pp := range p.members() {
+		pp.Close()
+	}
+}
+
+// healthyCount returns the number of proxies that aren't ejected.
+func (p *proxyPool) healthyCount() int64 {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var healthy int64
+	for _, pp := range p.proxies {
+		if pp.healthy(p.maxFailures, now) {
+			healthy++
+		}
+	}
+	return healthy
+}
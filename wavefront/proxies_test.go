@@ -0,0 +1,289 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+)
+
+// fakeProxyPool returns a pool of fake senders, one per endpoint.
+func fakeProxyPool(t *testing.T, proxy *config.Params_WavefrontProxy, count int) (*proxyPool, []*fakeSender) {
+	var endpoints []proxyEndpoint
+	fakes := make(map[proxyEndpoint]*fakeSender)
+	var ordered []*fakeSender
+	for i := 0; i < count; i++ {
+		endpoint := proxyEndpoint{host: fmt.Sprintf("10.0.0.%d", i+1), port: 2878}
+		endpoints = append(endpoints, endpoint)
+		fakes[endpoint] = &fakeSender{}
+		ordered = append(ordered, fakes[endpoint])
+	}
+	resolve := func() ([]proxyEndpoint, error) { return endpoints, nil }
+	pool, err := newProxyPool(proxy, resolve, func(endpoint proxyEndpoint) (senders.Sender, error) {
+		return fakes[endpoint], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool, ordered
+}
+
+func TestResolveProxyEndpoints(t *testing.T) {
+	table := []struct {
+		addresses []string
+		endpoints []proxyEndpoint
+		err       error
+	}{
+		{[]string{"127.0.0.1:2878"}, []proxyEndpoint{{"127.0.0.1", 2878}}, nil},
+		{[]string{"127.0.0.1:2878", "127.0.0.2:2878", "127.0.0.1:2878"}, []proxyEndpoint{{"127.0.0.1", 2878}, {"127.0.0.2", 2878}}, nil},
+		{[]string{"[fd00::1]:2878"}, []proxyEndpoint{{"fd00::1", 2878}}, nil},
+		{[]string{"[::1]:http"}, []proxyEndpoint{{"::1", 80}}, nil},
+		{[]string{"127.0.0.1"}, nil, fmt.Errorf("invalid proxy address: address 127.0.0.1: missing port in address")},
+		{[]string{"fd00::1:2878"}, nil, fmt.Errorf("invalid proxy address: address fd00::1:2878: too many colons in address")},
+	}
+
+	for _, entry := range table {
+		endpoints, err := resolveProxyEndpoints(entry.addresses)
+		if !reflect.DeepEqual(endpoints, entry.endpoints) || fmt.Sprint(err) != fmt.Sprint(entry.err) {
+			t.Errorf("Resolution failed for %v, got: %v, %v, want: %v, %v.", entry.addresses, endpoints, err, entry.endpoints, entry.err)
+		}
+	}
+
+	if host := (proxyEndpoint{"fd00::1", 2878}).dialHost(); host != "[fd00::1]" {
+		t.Errorf("Expected IPv6 hosts to be bracketed, got: %s.", host)
+	}
+}
+
+func TestProxyPoolRoundRobin(t *testing.T) {
+	pool, fakes := fakeProxyPool(t, &config.Params_WavefrontProxy{}, 3)
+	defer pool.Close()
+	for i := 0; i < 6; i++ {
+		if err := pool.SendMetric("metric", 1, 0, "", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, fake := range fakes {
+		if len(fake.sent) != 2 {
+			t.Errorf("Expected the points to be spread evenly, proxy %d got: %d points.", i, len(fake.sent))
+		}
+	}
+}
+
+func TestProxyPoolConsistentHash(t *testing.T) {
+	pool, fakes := fakeProxyPool(t, &config.Params_WavefrontProxy{LoadBalancing: config.CONSISTENT_HASH}, 3)
+	defer pool.Close()
+
+	proxyOf := func(name string) int {
+		for _, fake := range fakes {
+			fake.sent = nil
+		}
+		if err := pool.SendDeltaCounter(name, 1, "", map[string]string{"tag": "value"}); err != nil {
+			t.Fatal(err)
+		}
+		for i, fake := range fakes {
+			if len(fake.sent) > 0 {
+				return i
+			}
+		}
+		return -1
+	}
+
+	proxies := make(map[string]int)
+	used := make(map[int]bool)
+	for i := 0; i < 30; i++ {
+		name := fmt.Sprintf("metric-%d", i)
+		proxies[name] = proxyOf(name)
+		used[proxies[name]] = true
+		if again := proxyOf(name); again != proxies[name] {
+			t.Errorf("Expected series %s to keep its proxy, got: %d, want: %d.", name, again, proxies[name])
+		}
+	}
+	if len(used) != 3 {
+		t.Errorf("Expected the series to be spread over the proxies, got: %v.", used)
+	}
+
+	// ejecting a proxy only moves its own series
+	fakes[0].sendErr = errTest
+	for i := 0; i < pool.maxFailures; i++ {
+		pool.record(pool.proxies[0], errTest)
+	}
+	for name, proxy := range proxies {
+		if moved := proxyOf(name); proxy != 0 && moved != proxy || proxy == 0 && moved == 0 {
+			t.Errorf("Unexpected proxy for series %s, got: %d, was: %d.", name, moved, proxy)
+		}
+	}
+}
+
+func TestProxyPoolEjection(t *testing.T) {
+	pool, fakes := fakeProxyPool(t, &config.Params_WavefrontProxy{MaxFailures: 2, EjectionTime: time.Hour}, 2)
+	defer pool.Close()
+	fakes[0].sendErr = errTest
+
+	// the points fail over to the healthy proxy
+	for i := 0; i < 10; i++ {
+		if err := pool.SendMetric("metric", 1, 0, "", nil); err != nil {
+			t.Errorf("Expected the point to fail over, got: %v.", err)
+		}
+	}
+	if len(fakes[1].sent) != 10 || pool.healthyCount() != 1 {
+		t.Errorf("Expected the failing proxy to be ejected, got: %d points sent, %d healthy.", len(fakes[1].sent), pool.healthyCount())
+	}
+	if failures := pool.proxies[0].failures; failures != 2 {
+		t.Errorf("Expected the ejected proxy to be left out, got: %d failures.", failures)
+	}
+
+	// the data fails if all the proxies fail
+	fakes[1].sendErr = errTest
+	for i := 0; i < 2; i++ {
+		if err := pool.SendMetric("metric", 1, 0, "", nil); err != errTest {
+			t.Errorf("Expected the point to fail, got: %v.", err)
+		}
+	}
+	if healthy := pool.healthyCount(); healthy != 0 {
+		t.Errorf("Expected all the proxies to be ejected, got: %d healthy.", healthy)
+	}
+
+	// the proxies are tried again once their ejection expires
+	fakes[0].sendErr, fakes[1].sendErr = nil, nil
+	for _, pp := range pool.proxies {
+		pp.ejectedUntil = time.Now()
+	}
+	if err := pool.SendMetric("metric", 1, 0, "", nil); err != nil {
+		t.Errorf("Expected the point to be sent, got: %v.", err)
+	}
+	if healthy := pool.healthyCount(); healthy != 2 {
+		t.Errorf("Expected the proxies to be put back in, got: %d healthy.", healthy)
+	}
+}
+
+func TestProxyPoolFlush(t *testing.T) {
+	pool, fakes := fakeProxyPool(t, &config.Params_WavefrontProxy{}, 2)
+	defer pool.Close()
+	fakes[0].err = errTest
+	if err := pool.Flush(); err != nil {
+		t.Errorf("Expected a flush to succeed with a healthy proxy, got: %v.", err)
+	}
+	fakes[1].err = errTest
+	if err := pool.Flush(); err == nil {
+		t.Errorf("Expected a flush to fail with no healthy proxy.")
+	}
+}
+
+func TestProxyPoolRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	sender, err := createProxySender(&config.Params_WavefrontProxy{Address: address, MaxFailures: 2, EjectionTime: time.Hour}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := sender.(*proxyPool)
+	defer pool.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := pool.SendMetric("metric", 1, 0, "source", nil); err == nil {
+			t.Errorf("Expected the point to be refused.")
+		}
+	}
+	// the sender of a refused proxy has nothing to flush, which doesn't
+	// make the proxy healthy again
+	for i := 0; i < 3; i++ {
+		if err := pool.Flush(); err == nil || !strings.Contains(err.Error(), "ejected") {
+			t.Errorf("Expected the flush to fail with the proxy ejected, got: %v.", err)
+		}
+	}
+	if healthy, failures := pool.healthyCount(), pool.proxies[0].failures; healthy != 0 || failures != 2 {
+		t.Errorf("Expected the refused proxy to stay ejected, got: %d healthy, %d failures.", healthy, failures)
+	}
+}
+
+func TestProxyPoolRefresh(t *testing.T) {
+	var mu sync.Mutex
+	resolutions := 0
+	endpoints := []proxyEndpoint{{"10.0.0.1", 2878}, {"10.0.0.2", 2878}}
+	resolve := func() ([]proxyEndpoint, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolutions++
+		return endpoints, nil
+	}
+	fakes := make(map[proxyEndpoint]*fakeSender)
+	create := func(endpoint proxyEndpoint) (senders.Sender, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fakes[endpoint] = &fakeSender{}
+		return fakes[endpoint], nil
+	}
+	pool, err := newProxyPool(&config.Params_WavefrontProxy{ResolveInterval: time.Hour}, resolve, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// a proxy moving to another IP is replaced, while the other one is kept
+	kept := pool.proxies[0]
+	mu.Lock()
+	moved := fakes[endpoints[1]]
+	endpoints = []proxyEndpoint{{"10.0.0.1", 2878}, {"10.0.0.3", 2878}}
+	mu.Unlock()
+	pool.refresh()
+
+	var got []proxyEndpoint
+	for _, pp := range pool.members() {
+		got = append(got, pp.endpoint)
+	}
+	if !reflect.DeepEqual(got, endpoints) {
+		t.Errorf("Expected the proxies to be resolved again, got: %v, want: %v.", got, endpoints)
+	}
+	if pool.members()[0] != kept {
+		t.Errorf("Expected the unchanged proxy to be kept.")
+	}
+	mu.Lock()
+	if !moved.closed || fakes[endpoints[0]].closed {
+		t.Errorf("Expected only the removed proxy to be closed.")
+	}
+	mu.Unlock()
+
+	// a failing proxy triggers a resolution
+	pool.mu.Lock()
+	pool.resolved = time.Time{}
+	pool.mu.Unlock()
+	mu.Lock()
+	before := resolutions
+	mu.Unlock()
+	pool.record(kept, errTest)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		after := resolutions
+		mu.Unlock()
+		if after > before {
+			return
+		}
+	}
+	t.Errorf("Expected a failing proxy to trigger a resolution.")
+}
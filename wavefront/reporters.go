@@ -0,0 +1,670 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+	"istio.io/istio/pkg/log"
+)
+
+const (
+	// reporterIdleTimeout is how long a reporter may stay unused before it's closed.
+	reporterIdleTimeout = 15 * time.Minute
+	// reporterSweepInterval is how often idle reporters are looked for.
+	reporterSweepInterval = time.Minute
+	// seriesSweepInterval is how often expired series and retired reporters
+	// are looked for.
+	seriesSweepInterval = 10 * time.Second
+	// reporterRetireTimeout is how long a reporter that was replaced by one
+	// with the same identity may stay unused before it's closed.
+	reporterRetireTimeout = time.Minute
+	// maxCachedKeys is the maximum number of reporter keys cached by raw
+	// handler configuration.
+	maxCachedKeys = 100
+	// initialRetryBackoff is the delay before retrying a failed reporter initialization.
+	initialRetryBackoff = time.Second
+	// maxRetryBackoff is the maximum delay between reporter initialization retries.
+	maxRetryBackoff = time.Minute
+)
+
+// errReportersClosed is returned for the requests handled after the reporters
+// were closed.
+var errReportersClosed = errors.New("wavefront reporters are shutting down")
+
+type (
+	// handlerReporter is a Wavefront reporter serving a distinct handler configuration.
+	handlerReporter struct {
+		reporter wf.WavefrontMetricsReporter
+		sender   *trackedSender
+		key      string
+		view     *detachableRegistry
+		series   *seriesLimiter
+		cfg      *config.Params
+		identity string
+		created  time.Time
+		lastUsed time.Time
+	}
+
+	// detachableRegistry is the view of a registry given to a reporter. Once
+	// detached, the reporter finds nothing left to report in it. The registry
+	// behind the view can be replaced, e.g. to hand its metrics over to
+	// another reporter.
+	detachableRegistry struct {
+		mu       sync.RWMutex
+		registry metrics.Registry
+		detached int32
+	}
+
+	// initFailure records a failed reporter initialization and when to retry it.
+	initFailure struct {
+		cfg      *config.Params
+		err      error
+		attempts uint
+		retryAt  time.Time
+	}
+
+	// pendingReporter is a reporter being created, which the requests with
+	// the same configuration wait for.
+	pendingReporter struct {
+		created chan struct{}
+		hr      *handlerReporter
+		err     error
+	}
+
+	// reporterCache keeps one reporter per distinct handler configuration and
+	// closes the reporters that no handler has used for a while.
+	reporterCache struct {
+		mu        sync.Mutex
+		reporters map[string]*handlerReporter
+		pending   map[string]*pendingReporter
+		failures  map[string]*initFailure
+		closed    bool
+		done      chan struct{}
+		closeOnce sync.Once
+
+		// keys caches the reporter keys by raw handler configuration
+		keys   map[string]string
+		keysMu sync.Mutex
+	}
+
+	// reporterFactory creates a reporter for the given registry, along with
+	// the sender it reports to, if known.
+	reporterFactory func(registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error)
+)
+
+// newDetachableRegistry returns the view of a new registry.
+func newDetachableRegistry() *detachableRegistry {
+	return &detachableRegistry{registry: metrics.NewRegistry()}
+}
+
+// current returns the registry behind the view, whether detached or not.
+func (r *detachableRegistry) current() metrics.Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.registry
+}
+
+// replace puts a new registry behind the view, holding the metrics of the
+// previous one that keep tells to keep, and returns the previous one.
+func (r *detachableRegistry) replace(keep func(key string) bool) metrics.Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.registry
+	r.registry = metrics.NewRegistry()
+	previous.Each(func(key string, metric interface{}) {
+		if keep(key) {
+			_ = r.registry.Register(key, metric)
+		}
+	})
+	return previous
+}
+
+// Each calls f for each registered metric, unless the view is detached.
+func (r *detachableRegistry) Each(f func(string, interface{})) {
+	if atomic.LoadInt32(&r.detached) == 0 {
+		r.current().Each(f)
+	}
+}
+
+// Get returns the metric registered with the given name, if any.
+func (r *detachableRegistry) Get(name string) interface{} {
+	return r.current().Get(name)
+}
+
+// GetAll returns the values of all the registered metrics.
+func (r *detachableRegistry) GetAll() map[string]map[string]interface{} {
+	return r.current().GetAll()
+}
+
+// GetOrRegister returns the metric registered with the given name, or
+// registers the given one.
+func (r *detachableRegistry) GetOrRegister(name string, metric interface{}) interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.registry.GetOrRegister(name, metric)
+}
+
+// Register registers the given metric with the given name.
+func (r *detachableRegistry) Register(name string, metric interface{}) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.registry.Register(name, metric)
+}
+
+// RunHealthchecks runs the registered healthchecks.
+func (r *detachableRegistry) RunHealthchecks() {
+	r.current().RunHealthchecks()
+}
+
+// Unregister unregisters the metric with the given name.
+func (r *detachableRegistry) Unregister(name string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.registry.Unregister(name)
+}
+
+// UnregisterAll unregisters all the metrics.
+func (r *detachableRegistry) UnregisterAll() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.registry.UnregisterAll()
+}
+
+// detach hides the registered metrics from the reporter.
+func (r *detachableRegistry) detach() {
+	atomic.StoreInt32(&r.detached, 1)
+}
+
+// close sends a final report and closes the reporter, which then closes its
+// sender. The reporter can't send the final report itself: reporting from its
+// event loop deadlocks as soon as there are metrics to report, so the registry
+// is detached before closing it. The registry is dropped along with the
+// reporter, so its meters and timers are stopped.
+func (hr *handlerReporter) close() {
+	hr.reporter.Report()
+	hr.view.detach()
+	hr.reporter.Close()
+	hr.view.current().Each(func(key string, metric interface{}) {
+		if !hr.isAdapterMetric(key) {
+			stopMetric(metric)
+		}
+	})
+}
+
+// isAdapterMetric tells whether the metric registered with the given key is
+// an adapter metric of the handler of the reporter, which the pipeline stats
+// hold as well.
+func (hr *handlerReporter) isAdapterMetric(key string) bool {
+	name, tags := wf.DecodeKey(key)
+	return tags[handlerTag] == handlerID(hr.key) && strings.HasPrefix(name, config.AdapterMetricsPrefix(hr.cfg))
+}
+
+// staysWith tells whether the metric registered with the given key stays with
+// the reporter when its metrics are handed over: the adapter metrics, system
+// stats included, do.
+func (hr *handlerReporter) staysWith(key string) bool {
+	name, _ := wf.DecodeKey(key)
+	return strings.HasPrefix(name, config.AdapterMetricsPrefix(hr.cfg))
+}
+
+// reporterKey returns the key identifying the reporter for a configuration.
+// Handlers share a reporter only if their whole reporter configuration is the
+// same, credentials included.
+func reporterKey(cfg *config.Params) string {
+	return hashConfig(reporterConfig(cfg))
+}
+
+// reporterIdentity returns the identity of the reporter for a configuration.
+// Reporters sending to the same server or proxy with the same source and
+// prefixes have the same identity, whatever their token or flush interval:
+// when a reporter is created, e.g. after a token rotation, the reporters with
+// the same identity hand their metrics over to it.
+func reporterIdentity(cfg *config.Params) string {
+	identityCfg := &config.Params{
+		Source:               cfg.Source,
+		Prefix:               cfg.Prefix,
+		AdapterMetricsPrefix: cfg.AdapterMetricsPrefix,
+	}
+	if direct := cfg.GetDirect(); direct != nil {
+		identityCfg.Credentials = &config.Params_Direct{
+			Direct: &config.Params_WavefrontDirect{Server: direct.Server},
+		}
+	} else if proxy := cfg.GetProxy(); proxy != nil {
+		identityCfg.Credentials = &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: proxy.Address, Addresses: proxy.Addresses},
+		}
+	}
+	return hashConfig(identityCfg)
+}
+
+// hashConfig returns the hex encoded hash of a configuration.
+func hashConfig(cfg *config.Params) string {
+	data, err := cfg.Marshal()
+	if err != nil {
+		// fall back to the text representation, which is just as stable
+		data = []byte(cfg.String())
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// reporterConfig returns the part of a configuration a reporter is built from.
+// The log level is global, and set apart from the reporters.
+func reporterConfig(cfg *config.Params) *config.Params {
+	reporterCfg := *cfg
+	reporterCfg.Metrics = nil
+	reporterCfg.MaxSeries = 0
+	reporterCfg.Tags = nil
+	reporterCfg.TagRewrites = nil
+	reporterCfg.TagFormats = nil
+	reporterCfg.Logs = nil
+	return &reporterCfg
+}
+
+// newReporterCache creates an empty reporter cache and starts sweeping idle reporters.
+func newReporterCache() *reporterCache {
+	rc := &reporterCache{
+		reporters: make(map[string]*handlerReporter),
+		pending:   make(map[string]*pendingReporter),
+		failures:  make(map[string]*initFailure),
+		done:      make(chan struct{}),
+		keys:      make(map[string]string),
+	}
+	go rc.sweep()
+	return rc
+}
+
+// key returns the reporter key of the given configuration, decoded from the
+// given raw configuration. The keys are cached by raw configuration, so that
+// each distinct handler configuration is only hashed once.
+func (rc *reporterCache) key(raw []byte, cfg *config.Params) string {
+	rc.keysMu.Lock()
+	key, found := rc.keys[string(raw)]
+	rc.keysMu.Unlock()
+	if found {
+		return key
+	}
+
+	key = reporterKey(cfg)
+	rc.keysMu.Lock()
+	defer rc.keysMu.Unlock()
+	if len(rc.keys) >= maxCachedKeys {
+		rc.keys = make(map[string]string)
+	}
+	rc.keys[string(raw)] = key
+	return key
+}
+
+// getOrCreate returns the reporter for the given configuration and its key,
+// creating it with create if it doesn't exist yet. Failed creations aren't retried until
+// their backoff expires. The creation, which may have to resolve and connect
+// to the server or proxies, doesn't hold up the requests of other handlers:
+// only the requests with the same configuration wait for it.
+func (rc *reporterCache) getOrCreate(key string, cfg *config.Params, create reporterFactory) (*handlerReporter, error) {
+	reporterCfg := reporterConfig(cfg)
+
+	rc.mu.Lock()
+	if rc.closed {
+		rc.mu.Unlock()
+		return nil, errReportersClosed
+	}
+	if hr, found := rc.reporters[key]; found {
+		hr.lastUsed = time.Now()
+		rc.mu.Unlock()
+		return hr, nil
+	}
+	if failure, failed := rc.failures[key]; failed && time.Now().Before(failure.retryAt) {
+		rc.mu.Unlock()
+		return nil, failure.err
+	}
+	if pending, found := rc.pending[key]; found {
+		rc.mu.Unlock()
+		<-pending.created
+		return pending.hr, pending.err
+	}
+	pending := &pendingReporter{created: make(chan struct{})}
+	rc.pending[key] = pending
+	rc.mu.Unlock()
+
+	defer close(pending.created)
+	pending.hr, pending.err = rc.create(key, cfg, reporterCfg, create)
+	return pending.hr, pending.err
+}
+
+// create creates the reporter with the given key, and adds it to the cache.
+// The reporters with the same identity, e.g. those of a previous token, hand
+// their metrics over to it right away, so that they aren't reported twice.
+func (rc *reporterCache) create(key string, cfg, reporterCfg *config.Params, create reporterFactory) (*handlerReporter, error) {
+	view := newDetachableRegistry()
+	reporter, sender, err := create(view)
+
+	rc.mu.Lock()
+	delete(rc.pending, key)
+
+	if err != nil {
+		backoff := rc.recordFailure(key, reporterCfg, err)
+		rc.mu.Unlock()
+		log.Warnf("couldn't create wavefront reporter %s, retrying in %s", key, backoff)
+		return nil, err
+	}
+	delete(rc.failures, key)
+
+	now := time.Now()
+	hr := &handlerReporter{
+		reporter: reporter,
+		sender:   sender,
+		key:      key,
+		view:     view,
+		series:   newSeriesLimiter(),
+		cfg:      reporterCfg,
+		identity: reporterIdentity(cfg),
+		created:  now,
+		lastUsed: now,
+	}
+	if rc.closed {
+		// the cache was closed in the meantime
+		rc.mu.Unlock()
+		go hr.close()
+		return nil, errReportersClosed
+	}
+	predecessors := make(map[string]*handlerReporter)
+	for otherKey, other := range rc.reporters {
+		if other.identity == hr.identity {
+			predecessors[otherKey] = other
+		}
+	}
+	rc.reporters[key] = hr
+	rc.mu.Unlock()
+
+	for predecessorKey, predecessor := range predecessors {
+		log.Infof("handing the metrics of wavefront reporter %s over to its successor %s", predecessorKey, key)
+		hr.takeOver(predecessor)
+	}
+	return hr, nil
+}
+
+// recordFailure records a failed reporter creation for the given key and
+// configuration, and returns how long to wait before retrying it. The backoff
+// doubles with every consecutive failure of the same configuration.
+func (rc *reporterCache) recordFailure(key string, cfg *config.Params, err error) time.Duration {
+	failure, failed := rc.failures[key]
+	if !failed || !failure.cfg.Equal(cfg) {
+		failure = &initFailure{cfg: cfg}
+		rc.failures[key] = failure
+	}
+	failure.err = err
+	failure.attempts++
+
+	backoff := initialRetryBackoff
+	for i := uint(1); i < failure.attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	failure.retryAt = time.Now().Add(backoff)
+	return backoff
+}
+
+// sweep periodically closes the reporters that have been idle for too long,
+// and the retired ones, and expires the series that weren't updated for too long.
+func (rc *reporterCache) sweep() {
+	ticker := time.NewTicker(reporterSweepInterval)
+	defer ticker.Stop()
+	seriesTicker := time.NewTicker(seriesSweepInterval)
+	defer seriesTicker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rc.closeIdle(time.Now().Add(-reporterIdleTimeout))
+		case <-seriesTicker.C:
+			rc.handOver(time.Now())
+			rc.expireSeries(time.Now())
+		case <-rc.done:
+			return
+		}
+	}
+}
+
+// handOver closes the retired reporters, handing the metrics they recorded
+// since over to their successor. A reporter is retired once it wasn't used for
+// reporterRetireTimeout since another reporter with the same identity was
+// created, e.g. after its token was rotated. Reporters with the same identity
+// used side by side, e.g. by handlers with different tokens, are left alone.
+func (rc *reporterCache) handOver(now time.Time) {
+	type handover struct {
+		key                string
+		retired, successor *handlerReporter
+	}
+	var handovers []handover
+
+	rc.mu.Lock()
+	for key, hr := range rc.reporters {
+		if now.Sub(hr.lastUsed) < reporterRetireTimeout {
+			continue
+		}
+		var successor *handlerReporter
+		for otherKey, other := range rc.reporters {
+			if otherKey != key && other.identity == hr.identity && hr.lastUsed.Before(other.created) &&
+				(successor == nil || other.created.After(successor.created)) {
+				successor = other
+			}
+		}
+		if successor != nil {
+			handovers = append(handovers, handover{key, hr, successor})
+		}
+	}
+	for _, h := range handovers {
+		delete(rc.reporters, h.key)
+	}
+	rc.mu.Unlock()
+
+	for _, h := range handovers {
+		log.Infof("closing wavefront reporter %s, replaced by its successor and unused since %s", h.key, h.retired.lastUsed)
+		h.successor.adopt(h.retired)
+		newHandlerStats(h.retired.key, h.retired.cfg).forget()
+	}
+}
+
+// takeOver takes over the metrics and series of a reporter with the same
+// identity, which keeps running, e.g. for the handlers still using it. Its
+// current values are reported first, and it then starts over with an empty
+// registry.
+func (hr *handlerReporter) takeOver(predecessor *handlerReporter) {
+	predecessor.reporter.Report()
+	hr.moveMetrics(predecessor, predecessor.view.replace(predecessor.staysWith))
+	hr.series.take(predecessor.series)
+}
+
+// adopt takes over the metrics, series and spooled data of a retired reporter
+// with the same identity, and closes it.
+func (hr *handlerReporter) adopt(retired *handlerReporter) {
+	retired.view.detach()
+	hr.moveMetrics(retired, retired.view.current())
+	hr.series.take(retired.series)
+	if hr.sender != nil && retired.sender != nil && hr.sender.spool != nil && retired.sender.spool != nil {
+		hr.sender.spool.adopt(retired.sender.spool)
+	}
+	retired.reporter.Close()
+}
+
+// moveMetrics moves the metrics of the given registry of another reporter
+// over to this one. The metrics this reporter doesn't have yet are moved over,
+// and the counts of the counters both have are added up. The metrics that stay
+// with the other reporter are left alone.
+func (hr *handlerReporter) moveMetrics(other *handlerReporter, registry metrics.Registry) {
+	registry.Each(func(key string, metric interface{}) {
+		if other.staysWith(key) {
+			return
+		}
+		if err := hr.view.Register(key, metric); err == nil {
+			return
+		}
+		existing := hr.view.Get(key)
+		if existing == metric {
+			return
+		}
+		if counter, ok := metric.(metrics.Counter); ok {
+			if adopter, ok := existing.(metrics.Counter); ok {
+				adopter.Inc(counter.Count())
+				return
+			}
+		}
+		stopMetric(metric)
+	})
+}
+
+// expireSeries expires the series of the reporters that weren't updated for
+// the number of flush intervals their configuration allows, if any.
+func (rc *reporterCache) expireSeries(now time.Time) {
+	rc.mu.Lock()
+	expiring := make(map[string]*handlerReporter)
+	for key, hr := range rc.reporters {
+		if hr.series != nil && hr.cfg.SeriesExpiryIntervals > 0 {
+			expiring[key] = hr
+		}
+	}
+	rc.mu.Unlock()
+
+	for key, hr := range expiring {
+		expiry := time.Duration(hr.cfg.SeriesExpiryIntervals) * config.FlushInterval(hr.cfg)
+		if expired := hr.series.expire(hr.view, expiry, now); expired > 0 {
+			log.Debugf("expired %d series of wavefront reporter %s", expired, key)
+			stats := newHandlerStats(hr.key, hr.cfg)
+			stats.attach(hr.reporter)
+			stats.counter("series.expired").Inc(int64(expired))
+		}
+	}
+}
+
+// closeIdle closes and forgets the reporters last used before the given time,
+// along with the failures that were due for a retry before then.
+func (rc *reporterCache) closeIdle(before time.Time) {
+	idle := make(map[string]*handlerReporter)
+
+	rc.mu.Lock()
+	for key, failure := range rc.failures {
+		if failure.retryAt.Before(before) {
+			delete(rc.failures, key)
+		}
+	}
+	for key, hr := range rc.reporters {
+		if hr.lastUsed.Before(before) {
+			idle[key] = hr
+			delete(rc.reporters, key)
+		}
+	}
+	rc.mu.Unlock()
+
+	for key, hr := range idle {
+		log.Infof("closing wavefront reporter %s, unused since %s", key, hr.lastUsed)
+		hr.close()
+		newHandlerStats(hr.key, hr.cfg).forget()
+	}
+}
+
+// close stops sweeping and closes all the reporters.
+func (rc *reporterCache) close() {
+	for _, hr := range rc.takeAll() {
+		hr.close()
+	}
+}
+
+// takeAll stops sweeping and creating reporters, and returns the reporters of
+// the cache, which is left empty.
+func (rc *reporterCache) takeAll() map[string]*handlerReporter {
+	rc.closeOnce.Do(func() {
+		close(rc.done)
+	})
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.closed = true
+	reporters := rc.reporters
+	rc.reporters = make(map[string]*handlerReporter)
+	return reporters
+}
+
+// status returns an error describing the reporters that couldn't be created
+// with the latest configuration, and the ones whose sender failed its last
+// flush. It returns nil if all of them are working.
+func (rc *reporterCache) status() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	var problems []string
+	for key, failure := range rc.failures {
+		problems = append(problems, fmt.Sprintf("reporter %s isn't initialized: %v", key, failure.err))
+	}
+	for key, hr := range rc.reporters {
+		if hr.sender == nil {
+			continue
+		}
+		if err := hr.sender.status(); err != nil {
+			problems = append(problems, fmt.Sprintf("reporter %s isn't flushing: %v", key, err))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// shutdown stops sweeping and closes all the reporters, and waits until they
+// have sent their final report and flushed their senders, or until ctx is
+// done. The reporters are closed concurrently.
+func (rc *reporterCache) shutdown(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, hr := range rc.takeAll() {
+		wg.Add(1)
+		go func(hr *handlerReporter) {
+			defer wg.Done()
+			hr.close()
+			if hr.sender != nil {
+				<-hr.sender.done()
+			}
+		}(hr)
+	}
+	closed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
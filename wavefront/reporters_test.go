@@ -0,0 +1,449 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+	"github.com/wavefronthq/wavefront-sdk-go/histogram"
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+)
+
+// fakeReporter is a no-op reporter that records whether it reported and was
+// closed. Its reports wait for block to be closed, if set.
+type fakeReporter struct {
+	registry metrics.Registry
+	block    chan struct{}
+	reported bool
+	closed   bool
+}
+
+func (r *fakeReporter) Start() {}
+func (r *fakeReporter) Close() { r.closed = true }
+
+func (r *fakeReporter) Report() {
+	if r.block != nil {
+		<-r.block
+	}
+	r.reported = true
+}
+
+func (r *fakeReporter) ErrorsCount() int64 { return 0 }
+
+func (r *fakeReporter) RegisterMetric(name string, metric interface{}, tags map[string]string) error {
+	return r.registry.Register(wf.EncodeKey(name, tags), metric)
+}
+
+func (r *fakeReporter) GetMetric(name string, tags map[string]string) interface{} {
+	return r.registry.Get(wf.EncodeKey(name, tags))
+}
+
+func (r *fakeReporter) GetOrRegisterMetric(name string, i interface{}, tags map[string]string) interface{} {
+	return r.registry.GetOrRegister(wf.EncodeKey(name, tags), i)
+}
+
+func (r *fakeReporter) UnregisterMetric(name string, tags map[string]string) {
+	r.registry.Unregister(wf.EncodeKey(name, tags))
+}
+
+// getOrCreate returns the reporter of the given configuration from the given
+// cache, creating it with create if needed.
+func getOrCreate(rc *reporterCache, cfg *config.Params, create reporterFactory) (*handlerReporter, error) {
+	return rc.getOrCreate(reporterKey(cfg), cfg, create)
+}
+
+// errTest is the error returned by the fakes.
+var errTest = errors.New("test error")
+
+// fakeSender is a sender whose flushes return err, and which refuses the data
+// sent with sendErr, recording the names of the data it accepts and whether
+// it was closed.
+type fakeSender struct {
+	senders.Sender
+	err     error
+	sendErr error
+	sent    []string
+	closed  bool
+}
+
+func (s *fakeSender) Flush() error { return s.err }
+func (s *fakeSender) Close()       { s.closed = true }
+
+func (s *fakeSender) send(name string) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.sent = append(s.sent, name)
+	return nil
+}
+
+func (s *fakeSender) SendMetric(name string, _ float64, _ int64, _ string, _ map[string]string) error {
+	return s.send(name)
+}
+
+func (s *fakeSender) SendDeltaCounter(name string, _ float64, _ string, _ map[string]string) error {
+	return s.send(name)
+}
+
+func (s *fakeSender) SendDistribution(name string, _ []histogram.Centroid, _ map[histogram.Granularity]bool, _ int64, _ string, _ map[string]string) error {
+	return s.send(name)
+}
+
+func TestReporterKey(t *testing.T) {
+	direct := func(token string) *config.Params_Direct {
+		return &config.Params_Direct{Direct: &config.Params_WavefrontDirect{
+			Server: "https://server.wavefront.com",
+			Token:  token,
+		}}
+	}
+	base := &config.Params{Credentials: direct("token-a"), Source: "istio", Prefix: "istio"}
+
+	table := []struct {
+		params *config.Params
+		same   bool
+	}{
+		{&config.Params{Credentials: direct("token-a"), Source: "istio", Prefix: "istio"}, true},
+		{&config.Params{Credentials: direct("token-a"), Source: "istio", Prefix: "istio",
+			Metrics: []*config.Params_MetricInfo{{InstanceName: "instance", Type: config.GAUGE}}}, true},
+		{&config.Params{Credentials: direct("token-a"), Source: "istio", Prefix: "istio",
+			Logs: &config.Params_Logs{Level: "debug"}}, true},
+		{&config.Params{Credentials: direct("token-b"), Source: "istio", Prefix: "istio"}, false},
+		{&config.Params{Credentials: direct("token-a"), Source: "istio", Prefix: "istio",
+			FlushInterval: time.Second}, false},
+		{&config.Params{Credentials: direct("token-a"), Source: "team", Prefix: "istio"}, false},
+		{&config.Params{Credentials: direct("token-a"), Source: "istio", Prefix: "team"}, false},
+		{&config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Address: "192.168.99.100:2878"},
+		}, Source: "istio", Prefix: "istio"}, false},
+		{&config.Params{Credentials: &config.Params_Proxy{
+			Proxy: &config.Params_WavefrontProxy{Addresses: []string{"192.168.99.100:2878", "192.168.99.101:2878"}},
+		}, Source: "istio", Prefix: "istio"}, false},
+	}
+
+	for _, entry := range table {
+		if same := reporterKey(entry.params) == reporterKey(base); same != entry.same {
+			t.Errorf("Key comparison failed for %v, got: %v, want: %v.", entry.params, same, entry.same)
+		}
+	}
+}
+
+func TestReporterCacheKey(t *testing.T) {
+	rc := newReporterCache()
+	defer rc.close()
+
+	cfg := &config.Params{Source: "first"}
+	raw, err := cfg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := rc.key(raw, cfg); key != reporterKey(cfg) {
+		t.Errorf("Expected the reporter key, got: %s.", key)
+	}
+	// the key of a raw configuration seen before isn't computed again
+	if key := rc.key(raw, &config.Params{Source: "second"}); key != reporterKey(cfg) {
+		t.Errorf("Expected the cached reporter key, got: %s.", key)
+	}
+
+	for i := 0; i < maxCachedKeys; i++ {
+		rc.key([]byte{byte(i)}, cfg)
+	}
+	if len(rc.keys) > maxCachedKeys {
+		t.Errorf("Expected at most %d cached keys, got: %d.", maxCachedKeys, len(rc.keys))
+	}
+}
+
+func TestReporterCache(t *testing.T) {
+	rc := newReporterCache()
+	defer rc.close()
+
+	cfg := func(source string, flushInterval time.Duration) *config.Params {
+		return &config.Params{Source: source, FlushInterval: flushInterval}
+	}
+	create := func(reporter *fakeReporter) reporterFactory {
+		return func(registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+			if reporter == nil {
+				return nil, nil, errors.New("create failed")
+			}
+			reporter.registry = registry
+			return reporter, nil, nil
+		}
+	}
+	reporterOf := func(hr *handlerReporter) wf.WavefrontMetricsReporter {
+		if hr == nil {
+			return nil
+		}
+		return hr.reporter
+	}
+
+	first := &fakeReporter{}
+	created, _ := getOrCreate(rc, cfg("first", time.Second), create(first))
+	if r := reporterOf(created); r != first {
+		t.Errorf("Expected the created reporter, got: %v.", r)
+	}
+	if hr, _ := getOrCreate(rc, cfg("first", time.Second), create(&fakeReporter{})); reporterOf(hr) != first {
+		t.Errorf("Expected the cached reporter, got: %v.", reporterOf(hr))
+	}
+	if hr, err := getOrCreate(rc, cfg("failed", time.Second), create(nil)); hr != nil || err == nil {
+		t.Errorf("Expected no reporter and an error, got: %v, %v.", hr, err)
+	}
+	if len(rc.reporters) != 1 {
+		t.Errorf("Expected failed reporters not to be cached, got: %d reporters.", len(rc.reporters))
+	}
+
+	// a changed configuration gets its own reporter
+	if hr, _ := getOrCreate(rc, cfg("first", time.Minute), create(&fakeReporter{})); reporterOf(hr) == first {
+		t.Errorf("Expected a new reporter for the changed configuration.")
+	}
+
+	meter := metrics.NewMeter()
+	_ = first.RegisterMetric("meter", meter, nil)
+	stats := testHandlerStats(cfg("first", time.Second))
+	stats.requestDone(time.Now(), "")
+	rc.closeIdle(time.Now().Add(time.Minute))
+	if !first.closed {
+		t.Errorf("Expected the idle reporter to be closed.")
+	}
+	if meter.Mark(1); meter.Count() != 0 {
+		t.Errorf("Expected the meters of the idle reporter to be stopped.")
+	}
+	pipelineStats.Each(func(key string, _ interface{}) {
+		if _, tags := wf.DecodeKey(key); tags[handlerTag] == stats.handler {
+			t.Errorf("Expected the adapter metrics of the idle reporter to be unregistered, got: %s.", key)
+		}
+	})
+
+	if len(rc.reporters) != 0 {
+		t.Errorf("Expected the idle reporters to be removed, got: %d reporters.", len(rc.reporters))
+	}
+}
+
+func TestReporterCacheHandOver(t *testing.T) {
+	rc := newReporterCache()
+	defer rc.close()
+
+	direct := func(token string) *config.Params {
+		return &config.Params{Source: "istio", Credentials: &config.Params_Direct{
+			Direct: &config.Params_WavefrontDirect{Server: "https://server.wavefront.com", Token: token},
+		}}
+	}
+	create := func(reporter *fakeReporter) reporterFactory {
+		return func(registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+			reporter.registry = registry
+			return reporter, nil, nil
+		}
+	}
+
+	old, rotated, other := &fakeReporter{}, &fakeReporter{}, &fakeReporter{}
+	oldHR, _ := getOrCreate(rc, direct("old"), create(old))
+	old.GetOrRegisterMetric("moved", metrics.NewCounter(), nil).(metrics.Counter).Inc(1)
+	old.GetOrRegisterMetric("summed", metrics.NewCounter(), nil).(metrics.Counter).Inc(2)
+	meter := metrics.NewMeter()
+	_ = old.RegisterMetric("meter", meter, nil)
+	oldHR.series.admit(&config.Params{}, &config.Params_MetricInfo{Type: config.COUNTER}, "moved", nil)
+
+	// the metrics are handed over as soon as the successor is created
+	rotatedHR, _ := getOrCreate(rc, direct("rotated"), create(rotated))
+	if !old.reported || old.closed {
+		t.Errorf("Expected the predecessor to report and keep running.")
+	}
+	if old.GetMetric("moved", nil) != nil || old.GetMetric("meter", nil) != nil {
+		t.Errorf("Expected the predecessor to start over with an empty registry.")
+	}
+	if counter, ok := rotated.GetMetric("moved", nil).(metrics.Counter); !ok || counter.Count() != 1 {
+		t.Errorf("Expected the metrics to be moved over, got: %v.", rotated.GetMetric("moved", nil))
+	}
+	if meter.Mark(1); meter.Count() != 1 || rotated.GetMetric("meter", nil) != meter {
+		t.Errorf("Expected the meters handed over to keep running.")
+	}
+	if _, found := rotatedHR.series.series[wf.EncodeKey("moved", nil)]; !found {
+		t.Errorf("Expected the series to be carried over.")
+	}
+	if len(oldHR.series.series) != 0 {
+		t.Errorf("Expected the predecessor series to be moved, got: %v.", oldHR.series.series)
+	}
+	rotated.GetOrRegisterMetric("summed", metrics.NewCounter(), nil).(metrics.Counter).Inc(3)
+
+	// what the predecessor records since is handed over once it's retired
+	old.GetOrRegisterMetric("summed", metrics.NewCounter(), nil).(metrics.Counter).Inc(4)
+	now := time.Now()
+	oldHR.lastUsed = now.Add(-2 * reporterRetireTimeout)
+	otherHR, _ := getOrCreate(rc, &config.Params{Source: "other"}, create(other))
+	otherHR.lastUsed = now.Add(-2 * reporterRetireTimeout)
+
+	rc.handOver(now)
+	if !old.closed {
+		t.Errorf("Expected the retired reporter to be closed.")
+	}
+	if _, found := rc.reporters[reporterKey(direct("old"))]; found {
+		t.Errorf("Expected the retired reporter to be removed.")
+	}
+	if _, found := rc.reporters[reporterKey(&config.Params{Source: "other"})]; !found || other.closed {
+		t.Errorf("Expected a reporter without successor to be kept.")
+	}
+	if counter := rotated.GetMetric("summed", nil).(metrics.Counter); counter.Count() != 9 {
+		t.Errorf("Expected the counters to be summed, got: %d.", counter.Count())
+	}
+
+	// reporters used side by side aren't handed over
+	teamA, teamB := &fakeReporter{}, &fakeReporter{}
+	_, _ = getOrCreate(rc, direct("team-a"), create(teamA))
+	_, _ = getOrCreate(rc, direct("team-b"), create(teamB))
+	_, _ = getOrCreate(rc, direct("team-a"), create(teamA))
+	rc.handOver(time.Now().Add(2 * reporterRetireTimeout))
+	if teamA.closed || teamB.closed {
+		t.Errorf("Expected reporters used side by side to be kept.")
+	}
+}
+
+func TestReporterCacheBackoff(t *testing.T) {
+	rc := newReporterCache()
+	defer rc.close()
+
+	attempts := 0
+	create := func(metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+		attempts++
+		return nil, nil, errors.New("create failed")
+	}
+
+	cfg := &config.Params{Source: "failing"}
+	for i := 0; i < 3; i++ {
+		if _, err := getOrCreate(rc, cfg, create); err == nil {
+			t.Errorf("Expected an error on attempt %d.", i)
+		}
+	}
+	if attempts != 1 {
+		t.Errorf("Expected retries to wait for the backoff, got: %d attempts.", attempts)
+	}
+
+	// a changed configuration is tried right away
+	if _, err := getOrCreate(rc, &config.Params{Source: "failing", FlushInterval: time.Second}, create); err == nil {
+		t.Errorf("Expected an error for the changed configuration.")
+	}
+	if attempts != 2 {
+		t.Errorf("Expected the changed configuration to be tried, got: %d attempts.", attempts)
+	}
+
+	table := []struct {
+		attempts uint
+		backoff  time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{100, time.Minute},
+	}
+	for _, entry := range table {
+		rc.failures["key"] = &initFailure{cfg: cfg, attempts: entry.attempts - 1}
+		if backoff := rc.recordFailure("key", cfg, errors.New("failed")); backoff != entry.backoff {
+			t.Errorf("Backoff failed for %d attempts, got: %v, want: %v.", entry.attempts, backoff, entry.backoff)
+		}
+	}
+}
+
+func TestReporterCacheConcurrency(t *testing.T) {
+	rc := newReporterCache()
+
+	release := make(chan struct{})
+	attempts := int32(0)
+	slow := func(registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+		atomic.AddInt32(&attempts, 1)
+		<-release
+		return &fakeReporter{registry: registry, block: make(chan struct{})}, nil, nil
+	}
+	fast := func(registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+		return &fakeReporter{registry: registry}, nil, nil
+	}
+
+	// a slow creation only holds up the requests with the same configuration
+	results := make(chan *handlerReporter, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			hr, _ := getOrCreate(rc, &config.Params{Source: "slow"}, slow)
+			results <- hr
+		}()
+	}
+	if _, err := getOrCreate(rc, &config.Params{Source: "fast"}, fast); err != nil {
+		t.Errorf("Expected a reporter to be created during a slow creation, got: %v.", err)
+	}
+	close(release)
+	first, second := <-results, <-results
+	if first == nil || first != second {
+		t.Fatalf("Expected the requests to share the created reporter, got: %v, %v.", first, second)
+	}
+	if attempts := atomic.LoadInt32(&attempts); attempts != 1 {
+		t.Errorf("Expected the reporter to be created once, got: %d attempts.", attempts)
+	}
+
+	// the final reports are abandoned once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := rc.shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected the shutdown to time out, got: %v.", err)
+	}
+	if _, err := getOrCreate(rc, &config.Params{Source: "late"}, fast); err == nil {
+		t.Errorf("Expected no reporters to be created after the shutdown.")
+	}
+	close(first.reporter.(*fakeReporter).block)
+}
+
+func TestReporterCacheStatus(t *testing.T) {
+	rc := newReporterCache()
+	defer rc.close()
+
+	if err := rc.status(); err != nil {
+		t.Errorf("Expected an empty cache to be ready, got: %v.", err)
+	}
+
+	sender := newTrackedSender(&fakeSender{}, time.Hour, nil, nil)
+	create := func(registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+		return &fakeReporter{registry: registry}, sender, nil
+	}
+	working := &config.Params{Source: "working"}
+	if _, err := getOrCreate(rc, working, create); err != nil {
+		t.Fatal(err)
+	}
+	sender.flush()
+	if err := rc.status(); err != nil {
+		t.Errorf("Expected a flushing reporter to be ready, got: %v.", err)
+	}
+
+	sender.Sender.(*fakeSender).err = errors.New("401 unauthorized")
+	sender.flush()
+	if err := rc.status(); err == nil || !strings.Contains(err.Error(), "isn't flushing: 401 unauthorized") {
+		t.Errorf("Expected a failing flush to be reported, got: %v.", err)
+	}
+	sender.Sender.(*fakeSender).err = nil
+	sender.flush()
+
+	failing := func(metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+		return nil, nil, errors.New("invalid token")
+	}
+	_, _ = getOrCreate(rc, &config.Params{Source: "working", FlushInterval: time.Second}, failing)
+	_, _ = getOrCreate(rc, &config.Params{Source: "failing"}, failing)
+	err := rc.status()
+	if err == nil || strings.Count(err.Error(), "isn't initialized: invalid token") != 2 {
+		t.Errorf("Expected the failed creations to be reported, got: %v.", err)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTagLength is the maximum length of a point tag key and value together.
+const maxTagLength = 254
+
+// The fields changed when sanitizing a metric.
+const (
+	fieldMetricName = "metric_name"
+	fieldTagKey     = "tag_key"
+	fieldTagValue   = "tag_value"
+)
+
+// validNameChar tells whether a character is valid in metric names and tag
+// keys, which may contain alphanumeric characters, hyphens, underscores and
+// dots.
+func validNameChar(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// sanitizeName replaces the characters that aren't valid in a name with
+// underscores. The given extra characters are considered valid as well.
+func sanitizeName(name, extra string) string {
+	return strings.Map(func(r rune) rune {
+		if validNameChar(r) || strings.ContainsRune(extra, r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// sanitizeMetricName returns a metric name Wavefront accepts. Metric names
+// are quoted when sent, so they may contain slashes and commas as well.
+func sanitizeMetricName(name string, stats *handlerStats) string {
+	sanitized := sanitizeName(name, "/,")
+	if sanitized != name {
+		stats.sanitized(fieldMetricName)
+	}
+	return sanitized
+}
+
+// truncate truncates a string to at most the given number of bytes, without
+// splitting characters.
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	for length > 0 && !utf8.RuneStart(s[length]) {
+		length--
+	}
+	return s[:length]
+}
+
+// sanitizeTags returns point tags Wavefront accepts. Invalid characters in
+// keys are replaced with underscores, line breaks in values with spaces, and
+// values are truncated so that keys and values together fit the length limit.
+// Tags whose value is blank, possibly once truncated, are dropped. If keys
+// collide once sanitized, the tag with the first original key wins.
+func sanitizeTags(tags map[string]string, stats *handlerStats) map[string]string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sanitized := make(map[string]string, len(tags))
+	for _, k := range keys {
+		v := tags[k]
+		key := truncate(sanitizeName(k, ""), maxTagLength-1)
+		if key != k {
+			stats.sanitized(fieldTagKey)
+		}
+		if _, collides := sanitized[key]; collides {
+			continue
+		}
+
+		value := truncate(strings.Map(func(r rune) rune {
+			if r == '\n' || r == '\r' {
+				return ' '
+			}
+			return r
+		}, v), maxTagLength-len(key))
+		if value != v {
+			stats.sanitized(fieldTagValue)
+		}
+		if value == "" {
+			if v == "" {
+				stats.sanitized(fieldTagValue)
+			}
+			continue
+		}
+		sanitized[key] = value
+	}
+	return sanitized
+}
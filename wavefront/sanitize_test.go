@@ -0,0 +1,81 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+)
+
+func TestSanitizeMetricName(t *testing.T) {
+	stats := testHandlerStats(&config.Params{Source: "sanitize-names"})
+
+	table := []struct {
+		in  string
+		out string
+	}{
+		{"request.count", "request.count"},
+		{"request/count,total", "request/count,total"},
+		{"request count:total", "request_count_total"},
+		{"requêtes", "requ_tes"},
+	}
+
+	for _, entry := range table {
+		if out := sanitizeMetricName(entry.in, stats); out != entry.out {
+			t.Errorf("Sanitization failed for %q, got: %q, want: %q.", entry.in, out, entry.out)
+		}
+	}
+	if count := stats.counter("sanitized", fieldTag, fieldMetricName).Count(); count != 2 {
+		t.Errorf("Expected 2 sanitized metric names, got: %d.", count)
+	}
+}
+
+func TestSanitizeTags(t *testing.T) {
+	long := strings.Repeat("é", 200)
+
+	table := []struct {
+		in  map[string]string
+		out map[string]string
+	}{
+		{map[string]string{"destination_service": "reviews"}, map[string]string{"destination_service": "reviews"}},
+		{map[string]string{"destination:service": "reviews"}, map[string]string{"destination_service": "reviews"}},
+		{map[string]string{"a b": "first", "a_b": "second"}, map[string]string{"a_b": "first"}},
+		{map[string]string{"message": "line\nbreak"}, map[string]string{"message": "line break"}},
+		// é takes 2 bytes, so that the value is truncated to 121 characters
+		{map[string]string{"request_path": long}, map[string]string{"request_path": long[:242]}},
+		// blank values are dropped, including the ones truncated away
+		{map[string]string{"destination_service": "reviews", "request_path": ""}, map[string]string{"destination_service": "reviews"}},
+		{map[string]string{strings.Repeat("k", 300): "é"}, map[string]string{}},
+	}
+
+	for _, entry := range table {
+		stats := testHandlerStats(&config.Params{Source: "sanitize-tags"})
+		if out := sanitizeTags(entry.in, stats); !reflect.DeepEqual(out, entry.out) {
+			t.Errorf("Sanitization failed for %v, got: %v, want: %v.", entry.in, out, entry.out)
+		}
+	}
+
+	stats := testHandlerStats(&config.Params{Source: "sanitize-tags"})
+	if count := stats.counter("sanitized", fieldTag, fieldTagKey).Count(); count != 3 {
+		t.Errorf("Expected 3 sanitized tag keys, got: %d.", count)
+	}
+	if count := stats.counter("sanitized", fieldTag, fieldTagValue).Count(); count != 4 {
+		t.Errorf("Expected 4 sanitized tag values, got: %d.", count)
+	}
+}
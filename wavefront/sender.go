@@ -0,0 +1,282 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/wavefronthq/wavefront-sdk-go/histogram"
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+	"istio.io/istio/pkg/log"
+)
+
+// errSenderClosed is returned when replaying spooled points to a closed sender.
+var errSenderClosed = errors.New("sender closed")
+
+// senderStats counts the data sent through a sender.
+type senderStats struct {
+	// points counts the points, delta counters and distributions sent
+	points metrics.Counter
+	// errors counts the data the sender refused, e.g. because its buffer is full
+	errors metrics.Counter
+	// flushErrors counts the failed flushes
+	flushErrors metrics.Counter
+}
+
+// trackedSender wraps a Wavefront sender to tell whether it's flushing
+// successfully, and when it's done closing. The reporters close their sender
+// asynchronously, after a final report.
+// With a spool, the data the sender refuses is spooled, and replayed once the
+// sender flushes successfully again. The data of the batches a direct sender
+// fails to flush goes back to its buffer instead, where it's dropped once the
+// buffer is full.
+type trackedSender struct {
+	senders.Sender
+	// buffered is the number of points and distributions accepted since the
+	// last successful flush of this wrapper, an estimate of the queue size
+	buffered  int64
+	stats     *senderStats
+	spool     *spool
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	// mu serializes the flushes and the closing of the sender
+	mu       sync.Mutex
+	isClosed bool
+	flushErr error
+
+	// closeMu guards replaying to the sender while it's closing, without
+	// waiting for flushes in progress
+	closeMu sync.RWMutex
+}
+
+// newTrackedSender wraps the given sender and flushes it every flush interval.
+// The data sent is counted with the given stats, if any, and the data refused
+// is spooled to the given spool, if any.
+func newTrackedSender(sender senders.Sender, flushInterval time.Duration, stats *senderStats, sp *spool) *trackedSender {
+	if stats == nil {
+		stats = &senderStats{
+			points:      metrics.NilCounter{},
+			errors:      metrics.NilCounter{},
+			flushErrors: metrics.NilCounter{},
+		}
+	}
+	s := &trackedSender{
+		Sender: sender,
+		stats:  stats,
+		spool:  sp,
+		closed: make(chan struct{}),
+	}
+	go s.monitor(flushInterval)
+	return s
+}
+
+// monitor periodically flushes the sender until it's closed, and starts
+// replaying the spool after a successful flush. The sender flushes on its own
+// as well, but doesn't tell whether it succeeded.
+func (s *trackedSender) monitor(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+			if s.spool != nil && s.status() == nil && s.spool.startReplay() {
+				go s.replay()
+			}
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// flush flushes the sender and records the outcome.
+func (s *trackedSender) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isClosed {
+		buffered := atomic.LoadInt64(&s.buffered)
+		s.flushErr = s.Sender.Flush()
+		if s.flushErr != nil {
+			s.stats.flushErrors.Inc(1)
+		} else {
+			atomic.AddInt64(&s.buffered, -buffered)
+		}
+	}
+}
+
+// count records the outcome of sending data.
+func (s *trackedSender) count(err error) error {
+	if err != nil {
+		s.stats.errors.Inc(1)
+	} else {
+		s.stats.points.Inc(1)
+		atomic.AddInt64(&s.buffered, 1)
+	}
+	return err
+}
+
+// SendMetric sends a metric, counting it, or spools it if it's refused.
+func (s *trackedSender) SendMetric(name string, value float64, ts int64, source string, tags map[string]string) error {
+	err := s.Sender.SendMetric(name, value, ts, source, tags)
+	if err != nil && s.spoolPoint(&spooledPoint{
+		Kind: spooledMetric, Name: name, Value: value, Timestamp: ts, Source: source, Tags: tags,
+	}) {
+		return nil
+	}
+	return s.count(err)
+}
+
+// SendDeltaCounter sends a delta counter, counting it, or spools it if it's refused.
+func (s *trackedSender) SendDeltaCounter(name string, value float64, source string, tags map[string]string) error {
+	err := s.Sender.SendDeltaCounter(name, value, source, tags)
+	if err != nil && s.spoolPoint(&spooledPoint{
+		Kind: spooledDeltaCounter, Name: name, Value: value, Source: source, Tags: tags,
+	}) {
+		return nil
+	}
+	return s.count(err)
+}
+
+// SendDistribution sends a distribution, counting it, or spools it if it's refused.
+func (s *trackedSender) SendDistribution(name string, centroids []histogram.Centroid, hgs map[histogram.Granularity]bool, ts int64, source string, tags map[string]string) error {
+	err := s.Sender.SendDistribution(name, centroids, hgs, ts, source, tags)
+	if err != nil {
+		p := &spooledPoint{
+			Kind: spooledDistribution, Name: name, Centroids: centroids, Timestamp: ts, Source: source, Tags: tags,
+		}
+		for hg, enabled := range hgs {
+			if enabled {
+				p.Granularities = append(p.Granularities, hg)
+			}
+		}
+		if s.spoolPoint(p) {
+			return nil
+		}
+	}
+	return s.count(err)
+}
+
+// spoolPoint spools data the sender refused, and returns whether it was
+// spooled. Invalid data, which the sender would never accept, isn't spooled.
+// Metrics and distributions without a timestamp are spooled with the current
+// time, so that they're replayed at the time they were sent; delta counters
+// have no timestamp, and are replayed as new increments.
+func (s *trackedSender) spoolPoint(p *spooledPoint) bool {
+	if s.spool == nil {
+		return false
+	}
+	if p.Kind != spooledDeltaCounter && p.Timestamp == 0 {
+		p.Timestamp = time.Now().Unix()
+	}
+	if err := p.validate(); err != nil {
+		return false
+	}
+	if err := s.spool.write(p); err != nil {
+		log.Debugf("couldn't spool %s %s: %v", p.Kind, p.Name, err)
+		return false
+	}
+	return true
+}
+
+// replay sends the spooled data to the sender, a batch every replay tick to
+// pace it, as long as the sender flushes successfully and accepts the data.
+func (s *trackedSender) replay() {
+	defer s.spool.stopReplay()
+	ticker := time.NewTicker(replayTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.closed:
+			return
+		}
+		if s.status() != nil {
+			return
+		}
+		if _, err := s.spool.replay(s.spool.replayBatch(), s.resend); err != nil {
+			log.Debugf("stopped replaying spool %s: %v", s.spool.dir, err)
+			return
+		}
+		if s.spool.depth() == 0 {
+			return
+		}
+	}
+}
+
+// resend sends spooled data to the sender, unless it's closed.
+func (s *trackedSender) resend(p *spooledPoint) error {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.isClosed {
+		return errSenderClosed
+	}
+	err := p.sendTo(s.Sender)
+	if err == nil {
+		atomic.AddInt64(&s.buffered, 1)
+	}
+	return err
+}
+
+// status returns the error of the last flush, if it failed.
+func (s *trackedSender) status() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flushErr
+}
+
+// approxQueueSize returns the number of points and distributions accepted
+// since the last successful flush, an estimate of the number waiting to be
+// flushed. It's approximate: a direct sender flushes at most one batch per
+// flush, so it under-reports a backlog, and the flushes the sender makes on its
+// own aren't seen, so it over-reports a queue they drained.
+func (s *trackedSender) approxQueueSize() int64 {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.isClosed {
+		return 0
+	}
+	return atomic.LoadInt64(&s.buffered)
+}
+
+// Close flushes the buffered data, closes the sender and its spool, if any,
+// and notifies the waiters.
+func (s *trackedSender) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closeMu.Lock()
+		s.Sender.Close()
+		s.isClosed = true
+		s.closeMu.Unlock()
+		s.mu.Unlock()
+		if s.spool != nil {
+			s.spool.close()
+		}
+		close(s.closed)
+	})
+}
+
+// done returns a channel that's closed once the sender is closed.
+func (s *trackedSender) done() <-chan struct{} {
+	return s.closed
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApproxQueueSize(t *testing.T) {
+	fake := &fakeSender{}
+	s := newTrackedSender(fake, time.Hour, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		if err := s.SendMetric("requestcount", 1, 0, "istio", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake.sendErr = errTest
+	_ = s.SendMetric("requestcount", 1, 0, "istio", nil)
+	if size := s.approxQueueSize(); size != 3 {
+		t.Errorf("Expected 3 buffered points, got: %d.", size)
+	}
+
+	fake.err = errTest
+	s.flush()
+	if size := s.approxQueueSize(); size != 3 {
+		t.Errorf("Expected the points to stay buffered after a failed flush, got: %d.", size)
+	}
+	fake.err = nil
+	s.flush()
+	if size := s.approxQueueSize(); size != 0 {
+		t.Errorf("Expected no buffered points after a flush, got: %d.", size)
+	}
+
+	fake.sendErr = nil
+	_ = s.SendMetric("requestcount", 1, 0, "istio", nil)
+	s.Close()
+	if size := s.approxQueueSize(); size != 0 {
+		t.Errorf("Expected no buffered points once closed, got: %d.", size)
+	}
+}
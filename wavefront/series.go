@@ -0,0 +1,235 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+	"istio.io/istio/pkg/log"
+)
+
+// overflowTagValue is the value of all the tags of the series that new series
+// are collapsed into once a series limit is reached.
+const overflowTagValue = "__overflow__"
+
+type (
+	// seriesLimiter keeps track of the active series of the metrics of a
+	// reporter, that is of their distinct tag sets, limits how many there may
+	// be, and expires the ones that aren't updated anymore. The handlers
+	// sharing the reporter with different series limits count their series
+	// separately.
+	seriesLimiter struct {
+		mu       sync.Mutex
+		series   map[string]*seriesInfo
+		byMetric map[string]int
+		// byLimit counts the series by the handler series limit they were
+		// admitted under
+		byLimit map[int32]int
+		// refused counts the instances of new series refused by metric, since
+		// its limit was last reached
+		refused map[string]int
+	}
+
+	// seriesInfo describes an active series.
+	seriesInfo struct {
+		name        string
+		tags        map[string]string
+		metricType  config.Params_MetricInfo_Type
+		granularity config.Params_MetricInfo_Granularity
+		// counted tells whether the series counts towards the series limits,
+		// which overflow series don't
+		counted bool
+		// maxSeries is the handler series limit the series was admitted under
+		maxSeries int32
+		lastUsed  time.Time
+	}
+)
+
+// newSeriesLimiter creates a series limiter with no active series.
+func newSeriesLimiter() *seriesLimiter {
+	return &seriesLimiter{
+		series:   make(map[string]*seriesInfo),
+		byMetric: make(map[string]int),
+		byLimit:  make(map[int32]int),
+		refused:  make(map[string]int),
+	}
+}
+
+// admit tells whether an instance of the given metric with the given tags may
+// be recorded. Instances of active series always are, and new series are
+// admitted until the series limit of the metric or of the handler is reached.
+// Admitted series are marked as used. The refused instances are logged along
+// with their count, less and less often.
+func (sl *seriesLimiter) admit(cfg *config.Params, m *config.Params_MetricInfo, name string, tags map[string]string) bool {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	if s, found := sl.series[wf.EncodeKey(name, tags)]; found {
+		s.lastUsed = time.Now()
+		return true
+	}
+
+	metricFull := m.MaxSeries > 0 && sl.byMetric[name] >= int(m.MaxSeries)
+	handlerFull := cfg.MaxSeries > 0 && sl.byLimit[cfg.MaxSeries] >= int(cfg.MaxSeries)
+	if !metricFull && !handlerFull {
+		sl.add(m, name, tags, cfg.MaxSeries)
+		return true
+	}
+
+	sl.refused[name]++
+	if refused := sl.refused[name]; refused&(refused-1) == 0 {
+		log.Warnf("series limit reached for metric %s with %d series, %d under the handler limit of %d, "+
+			"%d instances of new series handled with %s so far", name, sl.byMetric[name], sl.byLimit[cfg.MaxSeries],
+			cfg.MaxSeries, refused, m.Overflow)
+	}
+	return false
+}
+
+// touch marks a series that doesn't count towards the series limits, such as
+// an overflow series, as used.
+func (sl *seriesLimiter) touch(m *config.Params_MetricInfo, name string, tags map[string]string) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	if s, found := sl.series[wf.EncodeKey(name, tags)]; found {
+		s.lastUsed = time.Now()
+		return
+	}
+	sl.series[wf.EncodeKey(name, tags)] = &seriesInfo{
+		name:        name,
+		tags:        tags,
+		metricType:  m.Type,
+		granularity: m.Granularity,
+		lastUsed:    time.Now(),
+	}
+}
+
+// add adds a series of the given metric, used now, counting towards the
+// given handler series limit.
+func (sl *seriesLimiter) add(m *config.Params_MetricInfo, name string, tags map[string]string, maxSeries int32) {
+	sl.series[wf.EncodeKey(name, tags)] = &seriesInfo{
+		name:        name,
+		tags:        tags,
+		metricType:  m.Type,
+		granularity: m.Granularity,
+		counted:     true,
+		maxSeries:   maxSeries,
+		lastUsed:    time.Now(),
+	}
+	sl.byMetric[name]++
+	sl.byLimit[maxSeries]++
+}
+
+// take moves the series of another limiter that this one doesn't have yet
+// over to this one, e.g. those of a reporter whose metrics were handed over.
+func (sl *seriesLimiter) take(other *seriesLimiter) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	for key, s := range other.series {
+		if _, found := sl.series[key]; !found {
+			sl.series[key] = s
+			if s.counted {
+				sl.byMetric[s.name]++
+				sl.byLimit[s.maxSeries]++
+			}
+		}
+	}
+	other.series = make(map[string]*seriesInfo)
+	other.byMetric = make(map[string]int)
+	other.byLimit = make(map[int32]int)
+	other.refused = make(map[string]int)
+}
+
+// expiry returns how long after it was last used the series expires, given
+// the expiry of the handler series.
+func (s *seriesInfo) expiry(expiry time.Duration) time.Duration {
+	if s.metricType != config.WAVEFRONT_HISTOGRAM {
+		return expiry
+	}
+
+	// distributions are reported once their aggregation interval is over
+	var pending time.Duration
+	switch s.granularity {
+	case config.HOUR:
+		pending = time.Hour
+	case config.DAY:
+		pending = 24 * time.Hour
+	default:
+		pending = time.Minute
+	}
+	if expiry < 2*pending {
+		return 2 * pending
+	}
+	return expiry
+}
+
+// registryKey returns the key of the series metric in the reporter registry.
+func (s *seriesInfo) registryKey() string {
+	if s.metricType == config.DELTA_COUNTER {
+		return wf.EncodeKey(wf.DeltaCounterName(s.name), s.tags)
+	}
+	return wf.EncodeKey(s.name, s.tags)
+}
+
+// expire unregisters from the given registry the series that weren't used for
+// the given expiry, and forgets them. The meters and timers are stopped. It
+// returns the number of expired series.
+func (sl *seriesLimiter) expire(registry metrics.Registry, expiry time.Duration, now time.Time) int {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	expired := 0
+	for key, s := range sl.series {
+		if now.Sub(s.lastUsed) < s.expiry(expiry) {
+			continue
+		}
+
+		registryKey := s.registryKey()
+		stopMetric(registry.Get(registryKey))
+		registry.Unregister(registryKey)
+		delete(sl.series, key)
+		if s.counted {
+			sl.byMetric[s.name]--
+			if sl.byMetric[s.name] == 0 {
+				delete(sl.byMetric, s.name)
+			}
+			sl.byLimit[s.maxSeries]--
+			if sl.byLimit[s.maxSeries] == 0 {
+				delete(sl.byLimit, s.maxSeries)
+			}
+			delete(sl.refused, s.name)
+		}
+		expired++
+	}
+	return expired
+}
+
+// overflowTags returns the tags of the series that the series with the given
+// tags is collapsed into.
+func overflowTags(tags map[string]string) map[string]string {
+	overflow := make(map[string]string, len(tags))
+	for k := range tags {
+		overflow[k] = overflowTagValue
+	}
+	return overflow
+}
@@ -0,0 +1,173 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"testing"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/metric"
+)
+
+func TestSeriesLimiter(t *testing.T) {
+	cfg := &config.Params{MaxSeries: 3}
+	other := &config.Params{MaxSeries: 5}
+	limited := &config.Params_MetricInfo{MaxSeries: 2}
+	unlimited := &config.Params_MetricInfo{}
+	tags := func(path string) map[string]string {
+		return map[string]string{"path": path}
+	}
+
+	table := []struct {
+		cfg      *config.Params
+		metric   *config.Params_MetricInfo
+		name     string
+		tags     map[string]string
+		admitted bool
+	}{
+		{cfg, limited, "requests", tags("/a"), true},
+		{cfg, limited, "requests", tags("/b"), true},
+		{cfg, limited, "requests", tags("/a"), true},
+		// the metric limit is reached
+		{cfg, limited, "requests", tags("/c"), false},
+		{cfg, unlimited, "latency", tags("/a"), true},
+		// the handler limit is reached
+		{cfg, unlimited, "latency", tags("/b"), false},
+		{cfg, unlimited, "latency", tags("/a"), true},
+		// a handler with another limit counts its series separately
+		{other, unlimited, "latency", tags("/b"), true},
+		{other, unlimited, "size", tags("/a"), true},
+		{cfg, unlimited, "size", tags("/b"), false},
+	}
+
+	sl := newSeriesLimiter()
+	for i, entry := range table {
+		if admitted := sl.admit(entry.cfg, entry.metric, entry.name, entry.tags); admitted != entry.admitted {
+			t.Errorf("Admission %d failed for %s %v, got: %v, want: %v.", i, entry.name, entry.tags, admitted, entry.admitted)
+		}
+	}
+	if refused := sl.refused["latency"]; refused != 1 {
+		t.Errorf("Expected the refused instances to be counted, got: %d.", refused)
+	}
+}
+
+func TestWriteMetricsSeriesLimit(t *testing.T) {
+	table := []struct {
+		overflow config.Params_MetricInfo_Overflow
+		series   map[string]float64
+	}{
+		{config.DROP, map[string]float64{"/a": 1}},
+		{config.COLLAPSE, map[string]float64{"/a": 1, overflowTagValue: 3}},
+	}
+
+	for _, entry := range table {
+		cfg := &config.Params{
+			Metrics: []*config.Params_MetricInfo{{
+				Name:         "requests",
+				InstanceName: "requests.instance.istio-system",
+				Type:         config.GAUGE,
+				MaxSeries:    1,
+				Overflow:     entry.overflow,
+			}},
+		}
+		reporter := &fakeReporter{registry: metrics.NewRegistry()}
+		hr := &handlerReporter{reporter: reporter, series: newSeriesLimiter()}
+
+		var insts []*metric.InstanceMsg
+		for i, path := range []string{"/a", "/b", "/c"} {
+			insts = append(insts, &metric.InstanceMsg{
+				Name:       "requests.instance.istio-system",
+				Value:      &policy.Value{Value: &policy.Value_Int64Value{Int64Value: int64(i + 1)}},
+				Dimensions: map[string]*policy.Value{"path": {Value: &policy.Value_StringValue{StringValue: path}}},
+			})
+		}
+		(&WavefrontAdapter{}).writeMetrics(hr, cfg, nil, insts, testHandlerStats(cfg))
+
+		count := 0
+		reporter.registry.Each(func(string, interface{}) { count++ })
+		if count != len(entry.series) {
+			t.Errorf("Expected %d series with %v, got: %d.", len(entry.series), entry.overflow, count)
+		}
+		for path, value := range entry.series {
+			gauge, ok := reporter.GetMetric("requests", map[string]string{"path": path}).(metrics.GaugeFloat64)
+			if !ok || gauge.Value() != value {
+				t.Errorf("Expected series %s to be %v with %v, got: %v.", path, value, entry.overflow, gauge)
+			}
+		}
+	}
+}
+
+func TestSeriesExpiry(t *testing.T) {
+	cfg := &config.Params{}
+	gauge := &config.Params_MetricInfo{Type: config.GAUGE, MaxSeries: 1}
+	delta := &config.Params_MetricInfo{Type: config.DELTA_COUNTER}
+	meter := &config.Params_MetricInfo{Type: config.METER}
+	distribution := &config.Params_MetricInfo{Type: config.WAVEFRONT_HISTOGRAM, Granularity: config.HOUR}
+	tags := map[string]string{"path": "/a"}
+
+	registry := metrics.NewRegistry()
+	reporter := &fakeReporter{registry: registry}
+	sl := newSeriesLimiter()
+	for _, entry := range []struct {
+		metric *config.Params_MetricInfo
+		name   string
+		value  interface{}
+	}{
+		{gauge, "gauge", metrics.NewGaugeFloat64()},
+		{delta, wf.DeltaCounterName("delta"), metrics.NewCounter()},
+		{meter, "meter", metrics.NewMeter()},
+		{distribution, "distribution", wf.NewHistogram()},
+	} {
+		name := entry.name
+		if entry.metric.Type == config.DELTA_COUNTER {
+			name = "delta"
+		}
+		if !sl.admit(cfg, entry.metric, name, tags) {
+			t.Fatalf("Expected series %s to be admitted.", name)
+		}
+		reporter.RegisterMetric(entry.name, entry.value, tags)
+	}
+	sl.touch(gauge, "gauge", overflowTags(tags))
+	reporter.RegisterMetric("gauge", metrics.NewGaugeFloat64(), overflowTags(tags))
+
+	if expired := sl.expire(registry, time.Minute, time.Now()); expired != 0 {
+		t.Errorf("Expected no series to expire yet, got: %d.", expired)
+	}
+	if sl.admit(cfg, gauge, "gauge", map[string]string{"path": "/b"}) {
+		t.Errorf("Expected the series limit to apply before the expiry.")
+	}
+
+	// the distribution is kept until its hourly aggregate is reported
+	if expired := sl.expire(registry, time.Minute, time.Now().Add(time.Hour)); expired != 4 {
+		t.Errorf("Expected 4 series to expire, got: %d.", expired)
+	}
+	count := 0
+	registry.Each(func(string, interface{}) { count++ })
+	if count != 1 || reporter.GetMetric("distribution", tags) == nil {
+		t.Errorf("Expected only the distribution to be left, got: %d series.", count)
+	}
+	if !sl.admit(cfg, gauge, "gauge", map[string]string{"path": "/b"}) {
+		t.Errorf("Expected expired series not to count towards the series limit.")
+	}
+
+	if expired := sl.expire(registry, time.Minute, time.Now().Add(3*time.Hour)); expired != 2 {
+		t.Errorf("Expected 2 series to expire, got: %d.", expired)
+	}
+}
@@ -0,0 +1,616 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	"github.com/wavefronthq/wavefront-sdk-go/histogram"
+	"github.com/wavefronthq/wavefront-sdk-go/senders"
+	"istio.io/istio/pkg/log"
+)
+
+const (
+	// spoolExtension is the extension of the spool segment files.
+	spoolExtension = ".spool"
+	// spoolSegments is the number of segments a full spool is split into, so
+	// that the oldest points are discarded a segment at a time.
+	spoolSegments = 10
+	// replayTick is how often spooled points are replayed, a fraction of the
+	// replay rate at a time.
+	replayTick = 100 * time.Millisecond
+)
+
+// The kinds of spooled data.
+const (
+	spooledMetric       = "metric"
+	spooledDeltaCounter = "delta_counter"
+	spooledDistribution = "distribution"
+)
+
+// The reasons spooled points are discarded.
+const (
+	reasonSpoolFull    = "spool_full"
+	reasonSpoolExpired = "spool_expired"
+	reasonSpoolCorrupt = "spool_corrupt"
+)
+
+// errSpoolFull is returned when a point doesn't fit in the spool.
+var errSpoolFull = errors.New("spool full")
+
+type (
+	// spooledPoint is a point, delta counter or distribution the sender
+	// couldn't send, as written to the spool.
+	spooledPoint struct {
+		Kind          string                  `json:"kind"`
+		Name          string                  `json:"name"`
+		Value         float64                 `json:"value,omitempty"`
+		Centroids     []histogram.Centroid    `json:"centroids,omitempty"`
+		Granularities []histogram.Granularity `json:"granularities,omitempty"`
+		Timestamp     int64                   `json:"ts,omitempty"`
+		Source        string                  `json:"source,omitempty"`
+		Tags          map[string]string       `json:"tags,omitempty"`
+		// SpooledAt is when the point was spooled, in Unix milliseconds.
+		SpooledAt int64 `json:"spooled_at"`
+	}
+
+	// spoolSegment is a spool file, holding the points spooled over a period
+	// of time, one JSON object per line.
+	spoolSegment struct {
+		path string
+		// oldest and newest are when the oldest point left in the segment
+		// and the newest one were spooled
+		oldest time.Time
+		newest time.Time
+		// size and points are the bytes and points left to replay
+		size   int64
+		points int64
+	}
+
+	// spoolStats counts the points going through a spool.
+	spoolStats struct {
+		written  metrics.Counter
+		replayed metrics.Counter
+		// discarded counts the discarded points, by reason
+		discarded map[string]metrics.Counter
+	}
+
+	// spool is a bounded on-disk queue of the points a sender couldn't send,
+	// replayed oldest first once the sender recovers. The points are appended
+	// to the newest segment, and the oldest segments are discarded once the
+	// spool is too large or too old.
+	spool struct {
+		dir string
+		// refs is the number of senders using the spool, guarded by spoolsMu
+		refs int
+
+		mu         sync.Mutex
+		maxSize    int64
+		maxAge     time.Duration
+		replayRate int
+		stats      *spoolStats
+		segments   []*spoolSegment
+		// writer appends to the last segment, if it's still open for writing
+		writer  *os.File
+		written int64
+		// offset is the number of bytes of the first segment already replayed
+		offset    int64
+		size      int64
+		points    int64
+		replaying bool
+	}
+)
+
+var (
+	// spools are the open spools, by directory. A spool is closed once the
+	// last sender using it is closed, and loaded again from its directory by
+	// the next one.
+	spools   = make(map[string]*spool)
+	spoolsMu sync.Mutex
+)
+
+// spoolDir returns the directory of the spool of the handler with the given
+// reporter key and configuration, if it has one.
+func spoolDir(key string, cfg *config.Params) string {
+	if cfg.Spool == nil {
+		return ""
+	}
+	return filepath.Join(cfg.Spool.Directory, handlerID(key))
+}
+
+// openSpool returns the spool in the given directory, loading the segments
+// left by a previous run if it isn't open yet. Its limits are updated with
+// the given configuration. The spool must be closed once it's not used anymore.
+func openSpool(dir string, cfg *config.Params_Spool, stats *spoolStats) (*spool, error) {
+	spoolsMu.Lock()
+	defer spoolsMu.Unlock()
+
+	sp, found := spools[dir]
+	if !found {
+		sp = &spool{dir: dir}
+		if err := sp.load(); err != nil {
+			return nil, fmt.Errorf("error opening spool %s: %v", dir, err)
+		}
+		spools[dir] = sp
+	}
+	sp.refs++
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.maxSize = config.SpoolMaxSize(cfg)
+	sp.maxAge = config.SpoolMaxAge(cfg)
+	sp.replayRate = config.SpoolReplayRate(cfg)
+	sp.stats = stats
+	return sp, nil
+}
+
+// close releases the spool. Once no sender uses it anymore, its last segment
+// is closed and it's forgotten, its segments staying on disk.
+func (sp *spool) close() {
+	spoolsMu.Lock()
+	defer spoolsMu.Unlock()
+
+	if sp.refs--; sp.refs > 0 {
+		return
+	}
+	if spools[sp.dir] == sp {
+		delete(spools, sp.dir)
+	}
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.seal()
+}
+
+// load creates the spool directory, or loads the segments it holds.
+func (sp *spool) load() error {
+	if err := os.MkdirAll(sp.dir, 0755); err != nil {
+		return err
+	}
+	files, err := ioutil.ReadDir(sp.dir)
+	if err != nil {
+		return err
+	}
+	// the segments are named after their creation time, so they're sorted oldest first
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), spoolExtension) {
+			continue
+		}
+		seg := &spoolSegment{
+			path:   filepath.Join(sp.dir, file.Name()),
+			newest: file.ModTime(),
+			size:   file.Size(),
+		}
+		if err := seg.scan(); err != nil {
+			log.Warnf("couldn't read spool segment %s, discarding it: %v", seg.path, err)
+			_ = os.Remove(seg.path)
+			continue
+		}
+		sp.segments = append(sp.segments, seg)
+		sp.size += seg.size
+		sp.points += seg.points
+	}
+	return nil
+}
+
+// scan counts the points of a segment left by a previous run, and reads when
+// the oldest was spooled.
+func (seg *spoolSegment) scan() error {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	seg.oldest = seg.newest
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			if seg.points == 0 {
+				var p spooledPoint
+				if json.Unmarshal(line, &p) == nil {
+					seg.oldest = spooledTime(p.SpooledAt)
+				}
+			}
+			seg.points++
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// spooledTime returns the time of the given Unix milliseconds.
+func spooledTime(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond))
+}
+
+// write appends a point to the spool. If the spool is full, the oldest
+// segments are discarded to make room for it.
+func (sp *spool) write(p *spooledPoint) error {
+	now := time.Now()
+	p.SpooledAt = now.UnixNano() / int64(time.Millisecond)
+	line, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	n := int64(len(line))
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.trim(now)
+	if sp.writer != nil && sp.written+n > sp.maxSize/spoolSegments {
+		sp.seal()
+	}
+	for sp.size+n > sp.maxSize && len(sp.segments) > 0 {
+		sp.discard(reasonSpoolFull)
+	}
+	if sp.size+n > sp.maxSize {
+		sp.stats.discarded[reasonSpoolFull].Inc(1)
+		return errSpoolFull
+	}
+
+	if sp.writer == nil {
+		if err := sp.create(now); err != nil {
+			return err
+		}
+	}
+	if _, err := sp.writer.Write(line); err != nil {
+		// don't append to a segment that may end with a partial line
+		sp.seal()
+		return err
+	}
+
+	seg := sp.segments[len(sp.segments)-1]
+	if seg.points == 0 {
+		seg.oldest = now
+	}
+	seg.newest = now
+	seg.size += n
+	seg.points++
+	sp.written += n
+	sp.size += n
+	sp.points++
+	sp.stats.written.Inc(1)
+	return nil
+}
+
+// create starts a new segment, and opens it for writing.
+func (sp *spool) create(now time.Time) error {
+	if err := os.MkdirAll(sp.dir, 0755); err != nil {
+		return err
+	}
+	for nanos := now.UnixNano(); ; nanos++ {
+		path := filepath.Join(sp.dir, fmt.Sprintf("%020d%s", nanos, spoolExtension))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		sp.writer = f
+		sp.written = 0
+		sp.segments = append(sp.segments, &spoolSegment{path: path, oldest: now, newest: now})
+		return nil
+	}
+}
+
+// seal stops writing to the last segment.
+func (sp *spool) seal() {
+	if sp.writer != nil {
+		if err := sp.writer.Close(); err != nil {
+			log.Warnf("couldn't close spool segment %s: %v", sp.writer.Name(), err)
+		}
+		sp.writer = nil
+		sp.written = 0
+	}
+}
+
+// remove deletes the oldest segment, and returns the number of points left in it.
+func (sp *spool) remove() int64 {
+	seg := sp.segments[0]
+	if len(sp.segments) == 1 {
+		sp.seal()
+	}
+	if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+		log.Warnf("couldn't remove spool segment %s: %v", seg.path, err)
+	}
+	sp.segments = sp.segments[1:]
+	sp.offset = 0
+	sp.size -= seg.size
+	sp.points -= seg.points
+	return seg.points
+}
+
+// discard deletes the oldest segment, counting its points as discarded for
+// the given reason.
+func (sp *spool) discard(reason string) {
+	if points := sp.remove(); points > 0 {
+		log.Warnf("discarded %d points from spool %s: %s", points, sp.dir, reason)
+		sp.stats.discarded[reason].Inc(points)
+	}
+}
+
+// trim discards the segments holding only points older than the age limit.
+func (sp *spool) trim(now time.Time) {
+	cutoff := now.Add(-sp.maxAge)
+	for len(sp.segments) > 0 && sp.segments[0].newest.Before(cutoff) {
+		sp.discard(reasonSpoolExpired)
+	}
+}
+
+// replay sends up to max spooled points, oldest first, with send. It stops
+// at the first point that can't be sent, which is kept for the next replay.
+// Points older than the age limit are discarded instead of being sent. It
+// returns the number of points sent.
+func (sp *spool) replay(max int, send func(*spooledPoint) error) (int, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	now := time.Now()
+	sp.trim(now)
+	cutoff := now.Add(-sp.maxAge)
+
+	sent := 0
+	for sent < max && len(sp.segments) > 0 {
+		if len(sp.segments) == 1 {
+			// the points spooled from now on go to a new segment
+			sp.seal()
+		}
+		seg := sp.segments[0]
+		done, err := sp.replaySegment(seg, max-sent, cutoff, send, &sent)
+		if err != nil {
+			return sent, err
+		}
+		if done {
+			sp.remove()
+		}
+	}
+	return sent, nil
+}
+
+// replaySegment sends up to max points of the oldest segment, from where the
+// previous replay stopped. It returns whether the segment was fully replayed.
+func (sp *spool) replaySegment(seg *spoolSegment, max int, cutoff time.Time, send func(*spooledPoint) error, sent *int) (bool, error) {
+	f, err := os.Open(seg.path)
+	if os.IsNotExist(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(sp.offset, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	r := bufio.NewReader(f)
+	for replayed := 0; replayed < max; replayed++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// a partial line was left by a failed write
+			if len(line) > 0 {
+				sp.stats.discarded[reasonSpoolCorrupt].Inc(1)
+			}
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+
+		var p spooledPoint
+		if err := json.Unmarshal(line, &p); err != nil || p.validate() != nil {
+			sp.stats.discarded[reasonSpoolCorrupt].Inc(1)
+		} else if spooledAt := spooledTime(p.SpooledAt); spooledAt.Before(cutoff) {
+			sp.stats.discarded[reasonSpoolExpired].Inc(1)
+		} else if err := send(&p); err != nil {
+			return false, err
+		} else {
+			seg.oldest = spooledAt
+			sp.stats.replayed.Inc(1)
+			*sent++
+		}
+
+		n := int64(len(line))
+		sp.offset += n
+		seg.size -= n
+		seg.points--
+		sp.size -= n
+		sp.points--
+	}
+	return false, nil
+}
+
+// adopt moves the segments of another spool into this one, e.g. those of a
+// retired reporter whose metrics were handed over, so that they're replayed
+// along with its own, oldest first. The part of the first segment of the
+// other spool that was replayed already is left out.
+func (sp *spool) adopt(other *spool) {
+	if sp == other {
+		return
+	}
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	other.seal()
+	for i, seg := range other.segments {
+		path := filepath.Join(sp.dir, filepath.Base(seg.path))
+		offset := int64(0)
+		if i == 0 {
+			offset = other.offset
+		}
+		if err := moveSegment(seg.path, path, offset); err != nil {
+			log.Warnf("couldn't move spool segment %s to %s: %v", seg.path, sp.dir, err)
+			continue
+		}
+		moved := *seg
+		moved.path = path
+		sp.insert(&moved)
+	}
+	other.segments = nil
+	other.offset = 0
+	other.size = 0
+	other.points = 0
+}
+
+// insert adds a segment to the spool, ordered by creation time, which its
+// name is. The segment being written, and the one being replayed, stay last
+// and first.
+func (sp *spool) insert(seg *spoolSegment) {
+	i := sort.Search(len(sp.segments), func(i int) bool {
+		return filepath.Base(sp.segments[i].path) > filepath.Base(seg.path)
+	})
+	if i == 0 && sp.offset > 0 {
+		i = 1
+	}
+	if sp.writer != nil && i == len(sp.segments) && i > 0 {
+		i--
+	}
+	sp.segments = append(sp.segments, nil)
+	copy(sp.segments[i+1:], sp.segments[i:])
+	sp.segments[i] = seg
+	sp.size += seg.size
+	sp.points += seg.points
+}
+
+// moveSegment moves a segment file, leaving out the given number of bytes
+// already replayed from its start.
+func moveSegment(from, to string, offset int64) error {
+	if offset == 0 {
+		return os.Rename(from, to)
+	}
+	data, err := ioutil.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(to, data[offset:], 0644); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+// startReplay returns whether the spool has points to replay and isn't being
+// replayed already, in which case the caller must replay it and call
+// stopReplay when it's done.
+func (sp *spool) startReplay() bool {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.replaying || sp.points == 0 {
+		return false
+	}
+	sp.replaying = true
+	return true
+}
+
+// stopReplay records that the spool isn't being replayed anymore.
+func (sp *spool) stopReplay() {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.replaying = false
+}
+
+// replayBatch returns the number of points to replay every replay tick.
+func (sp *spool) replayBatch() int {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	batch := sp.replayRate * int(replayTick) / int(time.Second)
+	if batch < 1 {
+		batch = 1
+	}
+	return batch
+}
+
+// depth returns the number of points in the spool.
+func (sp *spool) depth() int64 {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.points
+}
+
+// bytes returns the size of the spool in bytes.
+func (sp *spool) bytes() int64 {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.size
+}
+
+// age returns how long ago the oldest point in the spool was spooled, in
+// seconds, or 0 if the spool is empty.
+func (sp *spool) age() int64 {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.points == 0 || len(sp.segments) == 0 {
+		return 0
+	}
+	return int64(time.Since(sp.segments[0].oldest) / time.Second)
+}
+
+// validate returns an error if a spooled point would never be accepted by
+// the sender, so that it isn't spooled. The point is formatted the way the
+// sender would; the senders fill in a missing source, as the placeholder
+// default source does here.
+func (p *spooledPoint) validate() error {
+	const defaultSource = "source"
+	var err error
+	switch p.Kind {
+	case spooledMetric, spooledDeltaCounter:
+		_, err = senders.MetricLine(p.Name, p.Value, p.Timestamp, p.Source, p.Tags, defaultSource)
+	case spooledDistribution:
+		_, err = senders.HistoLine(p.Name, p.Centroids, p.granularities(), p.Timestamp, p.Source, p.Tags, defaultSource)
+	default:
+		err = fmt.Errorf("unknown spooled data %q", p.Kind)
+	}
+	return err
+}
+
+// granularities returns the histogram granularities of a spooled distribution.
+func (p *spooledPoint) granularities() map[histogram.Granularity]bool {
+	hgs := make(map[histogram.Granularity]bool, len(p.Granularities))
+	for _, hg := range p.Granularities {
+		hgs[hg] = true
+	}
+	return hgs
+}
+
+// sendTo sends a spooled point with the given sender.
+func (p *spooledPoint) sendTo(sender senders.Sender) error {
+	switch p.Kind {
+	case spooledMetric:
+		return sender.SendMetric(p.Name, p.Value, p.Timestamp, p.Source, p.Tags)
+	case spooledDeltaCounter:
+		return sender.SendDeltaCounter(p.Name, p.Value, p.Source, p.Tags)
+	case spooledDistribution:
+		return sender.SendDistribution(p.Name, p.Centroids, p.granularities(), p.Timestamp, p.Source, p.Tags)
+	}
+	return p.validate()
+}
@@ -0,0 +1,243 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	"github.com/wavefronthq/wavefront-sdk-go/histogram"
+)
+
+// tempSpool opens a spool in a new temporary directory, and returns it along
+// with a function removing the directory.
+func tempSpool(t *testing.T, cfg *config.Params_Spool) (*spool, *spoolStats, func()) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := testHandlerStats(&config.Params{Source: dir}).spoolStats()
+	sp, err := openSpool(dir, cfg, stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sp, stats, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestSpoolReplay(t *testing.T) {
+	sp, stats, cleanup := tempSpool(t, &config.Params_Spool{MaxSize: 2000})
+	defer cleanup()
+
+	var want []string
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("metric-%d", i)
+		if err := sp.write(&spooledPoint{Kind: spooledMetric, Name: name, Value: 1, Timestamp: 1}); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, name)
+	}
+	if depth := sp.depth(); depth != 10 {
+		t.Errorf("Expected the points to be spooled, got: %d points.", depth)
+	}
+	if len(sp.segments) < 2 {
+		t.Errorf("Expected the spool to be split into segments, got: %d segments.", len(sp.segments))
+	}
+
+	// a spool opened again, e.g. after a restart, finds the spooled points
+	reopened := &spool{dir: sp.dir}
+	if err := reopened.load(); err != nil || reopened.points != 10 || reopened.size != sp.bytes() {
+		t.Errorf("Expected the spool to be loaded, got: %d points, %d bytes, %v.", reopened.points, reopened.size, err)
+	}
+
+	var sent []string
+	failing := func(p *spooledPoint) error { return errTest }
+	if n, err := sp.replay(5, failing); n != 0 || err != errTest {
+		t.Errorf("Expected the replay to stop at the first failure, got: %d, %v.", n, err)
+	}
+	send := func(p *spooledPoint) error {
+		sent = append(sent, p.Name)
+		return nil
+	}
+	if n, err := sp.replay(4, send); n != 4 || err != nil {
+		t.Errorf("Expected a batch to be replayed, got: %d, %v.", n, err)
+	}
+	if n, err := sp.replay(100, send); n != 6 || err != nil {
+		t.Errorf("Expected the rest to be replayed, got: %d, %v.", n, err)
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Expected the points to be replayed oldest first, got: %v, want: %v.", sent, want)
+	}
+	if sp.depth() != 0 || sp.bytes() != 0 || sp.age() != 0 || len(sp.segments) != 0 {
+		t.Errorf("Expected the spool to be empty, got: %d points, %d bytes, %d segments.", sp.depth(), sp.bytes(), len(sp.segments))
+	}
+	if files, _ := filepath.Glob(filepath.Join(sp.dir, "*"+spoolExtension)); len(files) != 0 {
+		t.Errorf("Expected the replayed segments to be removed, got: %v.", files)
+	}
+	if written, replayed := stats.written.Count(), stats.replayed.Count(); written != 10 || replayed != 10 {
+		t.Errorf("Expected the spooled points to be counted, got: %d written, %d replayed.", written, replayed)
+	}
+}
+
+func TestSpoolLimits(t *testing.T) {
+	sp, stats, cleanup := tempSpool(t, &config.Params_Spool{MaxSize: 1000})
+	defer cleanup()
+
+	for i := 0; i < 100; i++ {
+		if err := sp.write(&spooledPoint{Kind: spooledMetric, Name: fmt.Sprintf("metric-%d", i), Timestamp: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if size := sp.bytes(); size > 1000 {
+		t.Errorf("Expected the spool to stay under its size limit, got: %d bytes.", size)
+	}
+	full := stats.discarded[reasonSpoolFull].Count()
+	if full == 0 || full+sp.depth() != 100 {
+		t.Errorf("Expected the oldest points to be discarded, got: %d discarded, %d left.", full, sp.depth())
+	}
+	if err := sp.write(&spooledPoint{Kind: spooledMetric, Name: string(make([]byte, 2000))}); err != errSpoolFull {
+		t.Errorf("Expected a point larger than the spool to be refused, got: %v.", err)
+	}
+
+	// the points older than the age limit are discarded
+	left := sp.depth()
+	sp.mu.Lock()
+	sp.maxAge = time.Millisecond
+	sp.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	if n, err := sp.replay(100, func(*spooledPoint) error { return nil }); n != 0 || err != nil {
+		t.Errorf("Expected no points to be replayed, got: %d, %v.", n, err)
+	}
+	if expired := stats.discarded[reasonSpoolExpired].Count(); expired != left || sp.depth() != 0 {
+		t.Errorf("Expected the old points to be discarded, got: %d discarded, %d left.", expired, sp.depth())
+	}
+}
+
+func TestSpoolAdopt(t *testing.T) {
+	sp, _, cleanup := tempSpool(t, &config.Params_Spool{MaxSize: 2000})
+	defer cleanup()
+	retired, _, cleanupRetired := tempSpool(t, &config.Params_Spool{MaxSize: 2000})
+	defer cleanupRetired()
+
+	for i := 0; i < 10; i++ {
+		if err := retired.write(&spooledPoint{Kind: spooledMetric, Name: fmt.Sprintf("retired-%d", i), Timestamp: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sp.write(&spooledPoint{Kind: spooledMetric, Name: "current", Timestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// the retired spool was partly replayed already
+	if n, err := retired.replay(2, func(*spooledPoint) error { return nil }); n != 2 || err != nil {
+		t.Fatalf("Expected a batch to be replayed, got: %d, %v.", n, err)
+	}
+
+	sp.adopt(retired)
+	if retired.depth() != 0 || len(retired.segments) != 0 {
+		t.Errorf("Expected the retired spool to be emptied, got: %d points.", retired.depth())
+	}
+	if depth := sp.depth(); depth != 9 {
+		t.Errorf("Expected the spooled points to be taken over, got: %d points.", depth)
+	}
+
+	var sent []string
+	send := func(p *spooledPoint) error {
+		sent = append(sent, p.Name)
+		return nil
+	}
+	if n, err := sp.replay(100, send); n != 9 || err != nil {
+		t.Errorf("Expected the adopted points to be replayed, got: %d, %v.", n, err)
+	}
+	want := []string{"retired-2", "retired-3", "retired-4", "retired-5", "retired-6", "retired-7", "retired-8", "retired-9", "current"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Expected the points to be replayed oldest first, got: %v, want: %v.", sent, want)
+	}
+}
+
+func TestSpoolClose(t *testing.T) {
+	sp, stats, cleanup := tempSpool(t, &config.Params_Spool{})
+	defer cleanup()
+	if err := sp.write(&spooledPoint{Kind: spooledMetric, Name: "metric", Timestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a spool used by another sender stays open
+	if shared, err := openSpool(sp.dir, &config.Params_Spool{}, stats); err != nil || shared != sp {
+		t.Fatalf("Expected the open spool to be shared, got: %v.", err)
+	}
+	sp.close()
+	if spools[sp.dir] != sp || sp.writer == nil {
+		t.Errorf("Expected the spool to stay open while used.")
+	}
+
+	sp.close()
+	if _, found := spools[sp.dir]; found || sp.writer != nil {
+		t.Errorf("Expected the spool to be closed and forgotten once unused.")
+	}
+	reopened, err := openSpool(sp.dir, &config.Params_Spool{}, stats)
+	if err != nil || reopened == sp || reopened.depth() != 1 {
+		t.Errorf("Expected the spool to be loaded again, got: %v.", err)
+	}
+	reopened.close()
+}
+
+func TestTrackedSenderSpool(t *testing.T) {
+	sp, stats, cleanup := tempSpool(t, &config.Params_Spool{ReplayRate: 100})
+	defer cleanup()
+
+	fake := &fakeSender{sendErr: errTest}
+	sender := newTrackedSender(fake, time.Hour, nil, sp)
+	defer sender.Close()
+
+	hgs := map[histogram.Granularity]bool{histogram.MINUTE: true}
+	centroids := []histogram.Centroid{{Value: 1, Count: 1}}
+	table := []struct {
+		send    func() error
+		spooled bool
+	}{
+		{func() error { return sender.SendMetric("metric", 1, 0, "", nil) }, true},
+		{func() error { return sender.SendDeltaCounter("counter", 1, "", nil) }, true},
+		{func() error { return sender.SendDistribution("distribution", centroids, hgs, 0, "", nil) }, true},
+		{func() error { return sender.SendMetric("", 1, 0, "", nil) }, false},
+		{func() error { return sender.SendDistribution("distribution", nil, hgs, 0, "", nil) }, false},
+	}
+	for i, entry := range table {
+		if err := entry.send(); (err == nil) != entry.spooled {
+			t.Errorf("Spooling failed for entry %d, got: %v, want spooled: %v.", i, err, entry.spooled)
+		}
+	}
+	if depth := sp.depth(); depth != 3 {
+		t.Errorf("Expected the refused data to be spooled, got: %d points.", depth)
+	}
+
+	// the spool is replayed once the sender accepts data again
+	fake.sendErr = nil
+	if !sp.startReplay() {
+		t.Fatal("Expected the spool to be replayed.")
+	}
+	sender.replay()
+	if want := []string{"metric", "counter", "distribution"}; !reflect.DeepEqual(fake.sent, want) {
+		t.Errorf("Expected the spooled data to be replayed, got: %v, want: %v.", fake.sent, want)
+	}
+	if replayed := stats.replayed.Count(); replayed != 3 {
+		t.Errorf("Expected the replayed data to be counted, got: %d.", replayed)
+	}
+}
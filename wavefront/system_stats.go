@@ -17,6 +17,7 @@ package wavefront
 
 import (
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/mackerelio/go-osstat/cpu"
@@ -28,9 +29,37 @@ import (
 // delay between memory and cpu metrics sample
 const delay = 60 * time.Second
 
-// createSystemStatsReporter creates a reporter that periodically flushes adapter system metrics to Wavefront.
-func createSystemStatsReporter(hostTags map[string]string) {
+var (
+	// systemStats holds the adapter system metrics, shared by all reporters.
+	systemStats = metrics.NewRegistry()
+	// systemStatsOnce ensures that system metrics are collected only once.
+	systemStatsOnce sync.Once
+)
+
+// createSystemStatsReporter registers the adapter system metrics with the given
+// reporter registry, so that they are flushed to Wavefront with the given prefix
+// and host tags. The collection starts with the first registered reporter.
+func createSystemStatsReporter(registry metrics.Registry, prefix string, hostTags map[string]string) {
+	systemStatsOnce.Do(collectSystemStats)
+	systemStats.Each(func(name string, metric interface{}) {
+		registry.GetOrRegister(wf.EncodeKey(prefix+name, hostTags), metric)
+	})
+}
+
+// collectSystemStats periodically samples the adapter system metrics.
+func collectSystemStats() {
 	log.Info("Preparing adapter metrics")
+
+	memAlloc := metrics.GetOrRegisterGauge("memory.alloc", systemStats)
+	memTotalAlloc := metrics.GetOrRegisterGauge("memory.totalalloc", systemStats)
+	memSys := metrics.GetOrRegisterGauge("memory.sys", systemStats)
+	memNumGC := metrics.GetOrRegisterGauge("memory.numgc", systemStats)
+	cpuUser := metrics.GetOrRegisterGaugeFloat64("cpu.user", systemStats)
+	cpuSystem := metrics.GetOrRegisterGaugeFloat64("cpu.system", systemStats)
+	cpuNice := metrics.GetOrRegisterGaugeFloat64("cpu.nice", systemStats)
+	cpuIdle := metrics.GetOrRegisterGaugeFloat64("cpu.idle", systemStats)
+	uptime := metrics.GetOrRegisterGaugeFloat64("uptime", systemStats)
+
 	ticker := time.NewTicker(delay)
 	go func() {
 		previous, err := cpu.Get()
@@ -45,17 +74,10 @@ func createSystemStatsReporter(hostTags map[string]string) {
 			var m runtime.MemStats
 			runtime.ReadMemStats(&m)
 
-			gauge := wf.GetOrRegisterMetric("adapter.memory.alloc", metrics.NewGauge(), hostTags).(metrics.Gauge)
-			gauge.Update(int64(m.Alloc))
-
-			gauge = wf.GetOrRegisterMetric("adapter.memory.totalalloc", metrics.NewGauge(), hostTags).(metrics.Gauge)
-			gauge.Update(int64(m.TotalAlloc))
-
-			gauge = wf.GetOrRegisterMetric("adapter.memory.sys", metrics.NewGauge(), hostTags).(metrics.Gauge)
-			gauge.Update(int64(m.Sys))
-
-			gauge = wf.GetOrRegisterMetric("adapter.memory.numgc", metrics.NewGauge(), hostTags).(metrics.Gauge)
-			gauge.Update(int64(m.NumGC))
+			memAlloc.Update(int64(m.Alloc))
+			memTotalAlloc.Update(int64(m.TotalAlloc))
+			memSys.Update(int64(m.Sys))
+			memNumGC.Update(int64(m.NumGC))
 
 			current, err := cpu.Get()
 			if err != nil {
@@ -64,20 +86,11 @@ func createSystemStatsReporter(hostTags map[string]string) {
 			}
 			total := float64(current.Total - previous.Total)
 
-			gaugeCPU := wf.GetOrRegisterMetric("adapter.cpu.user", metrics.NewGaugeFloat64(), hostTags).(metrics.GaugeFloat64)
-			gaugeCPU.Update(float64(current.User-previous.User) / total)
-
-			gaugeCPU = wf.GetOrRegisterMetric("adapter.cpu.system", metrics.NewGaugeFloat64(), hostTags).(metrics.GaugeFloat64)
-			gaugeCPU.Update(float64(current.System-previous.System) / total)
-
-			gaugeCPU = wf.GetOrRegisterMetric("adapter.cpu.nice", metrics.NewGaugeFloat64(), hostTags).(metrics.GaugeFloat64)
-			gaugeCPU.Update(float64(current.Nice-previous.Nice) / total)
-
-			gaugeCPU = wf.GetOrRegisterMetric("adapter.cpu.idle", metrics.NewGaugeFloat64(), hostTags).(metrics.GaugeFloat64)
-			gaugeCPU.Update(float64(current.Idle-previous.Idle) / total)
-
-			gaugeCPU = wf.GetOrRegisterMetric("adapter.uptime", metrics.NewGaugeFloat64(), hostTags).(metrics.GaugeFloat64)
-			gaugeCPU.Update(time.Since(startTime).Seconds())
+			cpuUser.Update(float64(current.User-previous.User) / total)
+			cpuSystem.Update(float64(current.System-previous.System) / total)
+			cpuNice.Update(float64(current.Nice-previous.Nice) / total)
+			cpuIdle.Update(float64(current.Idle-previous.Idle) / total)
+			uptime.Update(time.Since(startTime).Seconds())
 
 			previous = current
 		}
@@ -0,0 +1,100 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+)
+
+// mergeTagRules returns the tag rules of a metric, given the handler default
+// ones. Non-empty include and exclude lists of the metric replace the default
+// ones, and its renames and static tags are merged with the default ones,
+// taking precedence.
+func mergeTagRules(defaults, rules *config.Params_TagRules) *config.Params_TagRules {
+	if defaults == nil {
+		return rules
+	}
+	if rules == nil {
+		return defaults
+	}
+
+	merged := &config.Params_TagRules{
+		Include: defaults.Include,
+		Exclude: defaults.Exclude,
+		Rename:  mergeMaps(defaults.Rename, rules.Rename),
+		Add:     mergeMaps(defaults.Add, rules.Add),
+	}
+	if len(rules.Include) > 0 {
+		merged.Include = rules.Include
+	}
+	if len(rules.Exclude) > 0 {
+		merged.Exclude = rules.Exclude
+	}
+	return merged
+}
+
+// mergeMaps returns the union of the given maps, the values of overrides
+// taking precedence.
+func mergeMaps(base, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// applyTagRules applies the given tag rules to the tags decoded from the
+// dimensions of an instance. The included dimensions are kept, then the
+// excluded ones dropped and the remaining ones renamed. Static tags are added
+// last, replacing the dimensions with the same key.
+func applyTagRules(rules *config.Params_TagRules, tags map[string]string) map[string]string {
+	if rules == nil {
+		return tags
+	}
+
+	var include map[string]bool
+	if len(rules.Include) > 0 {
+		include = make(map[string]bool, len(rules.Include))
+		for _, k := range rules.Include {
+			include[k] = true
+		}
+	}
+	exclude := make(map[string]bool, len(rules.Exclude))
+	for _, k := range rules.Exclude {
+		exclude[k] = true
+	}
+
+	result := make(map[string]string, len(tags)+len(rules.Add))
+	for k, v := range tags {
+		if (include != nil && !include[k]) || exclude[k] {
+			continue
+		}
+		if renamed, ok := rules.Rename[k]; ok {
+			k = renamed
+		}
+		result[k] = v
+	}
+	for k, v := range rules.Add {
+		result[k] = v
+	}
+	return result
+}
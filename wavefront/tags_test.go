@@ -0,0 +1,89 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+)
+
+func TestApplyTagRules(t *testing.T) {
+	tags := map[string]string{
+		"destination_service": "reviews",
+		"source_version":      "v1",
+		"response_code":       "200",
+	}
+
+	table := []struct {
+		rules *config.Params_TagRules
+		out   map[string]string
+	}{
+		{nil, tags},
+		{&config.Params_TagRules{Include: []string{"destination_service", "response_code"}},
+			map[string]string{"destination_service": "reviews", "response_code": "200"}},
+		{&config.Params_TagRules{Exclude: []string{"source_version"}},
+			map[string]string{"destination_service": "reviews", "response_code": "200"}},
+		{&config.Params_TagRules{Include: []string{"destination_service", "source_version"}, Exclude: []string{"source_version"}},
+			map[string]string{"destination_service": "reviews"}},
+		{&config.Params_TagRules{
+			Exclude: []string{"source_version"},
+			Rename:  map[string]string{"destination_service": "service"},
+			Add:     map[string]string{"cluster": "prod-us-east", "response_code": "any"},
+		}, map[string]string{"service": "reviews", "response_code": "any", "cluster": "prod-us-east"}},
+	}
+
+	for _, entry := range table {
+		if out := applyTagRules(entry.rules, tags); !reflect.DeepEqual(out, entry.out) {
+			t.Errorf("Applying %v failed, got: %v, want: %v.", entry.rules, out, entry.out)
+		}
+	}
+}
+
+func TestMergeTagRules(t *testing.T) {
+	defaults := &config.Params_TagRules{
+		Exclude: []string{"source_version"},
+		Rename:  map[string]string{"destination_service": "service"},
+		Add:     map[string]string{"cluster": "prod-us-east", "env": "prod"},
+	}
+	rules := &config.Params_TagRules{
+		Include: []string{"destination_service"},
+		Add:     map[string]string{"env": "staging"},
+	}
+
+	table := []struct {
+		defaults *config.Params_TagRules
+		rules    *config.Params_TagRules
+		out      *config.Params_TagRules
+	}{
+		{nil, nil, nil},
+		{defaults, nil, defaults},
+		{nil, rules, rules},
+		{defaults, rules, &config.Params_TagRules{
+			Include: []string{"destination_service"},
+			Exclude: []string{"source_version"},
+			Rename:  map[string]string{"destination_service": "service"},
+			Add:     map[string]string{"cluster": "prod-us-east", "env": "staging"},
+		}},
+	}
+
+	for _, entry := range table {
+		if out := mergeTagRules(entry.defaults, entry.rules); !reflect.DeepEqual(out, entry.out) {
+			t.Errorf("Merging %v into %v failed, got: %v, want: %v.", entry.rules, entry.defaults, out, entry.out)
+		}
+	}
+}
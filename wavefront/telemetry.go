@@ -0,0 +1,199 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+)
+
+const (
+	// handlerTag is the tag identifying the handler of an adapter metric.
+	handlerTag = "handler"
+	// reasonTag is the tag telling why a request failed or an instance was dropped.
+	reasonTag = "reason"
+	// fieldTag is the tag telling what was changed when sanitizing a metric.
+	fieldTag = "field"
+	// unknownHandler identifies the handler of the requests whose configuration
+	// couldn't be read.
+	unknownHandler = "unknown"
+	// handlerIDLength is the length of the handler identifiers.
+	handlerIDLength = 12
+)
+
+// The reasons a request fails or an instance is dropped.
+const (
+	reasonInvalidConfig       = "invalid_config"
+	reasonReporterUnavailable = "reporter_unavailable"
+	reasonInvalidMetrics      = "invalid_metrics"
+	reasonInvalidTagRewrites  = "invalid_tag_rewrites"
+	reasonUnknownInstance     = "unknown_instance"
+	reasonConvertFailure      = "convert_failure"
+	reasonTranslateFailure    = "translate_failure"
+	reasonUnsupportedType     = "unsupported_type"
+	reasonSeriesLimit         = "series_limit"
+	reasonTypeMismatch        = "type_mismatch"
+)
+
+// pipelineStats holds the adapter metrics about the requests it handles and
+// the data it sends, tagged by handler. Each reporter reports the metrics of
+// its own handler.
+var pipelineStats = metrics.NewRegistry()
+
+// handlerStats records the adapter metrics of a handler.
+type handlerStats struct {
+	handler  string
+	prefix   string
+	reporter wf.WavefrontMetricsReporter
+}
+
+// handlerID returns a short identifier of the handler with the given reporter
+// key, which is its beginning.
+func handlerID(key string) string {
+	return key[:handlerIDLength]
+}
+
+// newHandlerStats returns the adapter metrics of the handler with the given
+// reporter key and configuration.
+func newHandlerStats(key string, cfg *config.Params) *handlerStats {
+	return &handlerStats{
+		handler: handlerID(key),
+		prefix:  config.AdapterMetricsPrefix(cfg),
+	}
+}
+
+// unknownHandlerStats returns the adapter metrics of the requests whose
+// configuration couldn't be read.
+func unknownHandlerStats() *handlerStats {
+	return &handlerStats{
+		handler: unknownHandler,
+		prefix:  config.DefaultAdapterMetricsPrefix,
+	}
+}
+
+// attach makes the metrics recorded from now on be reported by the given reporter.
+func (hs *handlerStats) attach(reporter wf.WavefrontMetricsReporter) {
+	hs.reporter = reporter
+}
+
+// reportTo registers all the metrics of the handler recorded so far with the
+// given reporter, and attaches it.
+func (hs *handlerStats) reportTo(reporter wf.WavefrontMetricsReporter) {
+	hs.attach(reporter)
+	pipelineStats.Each(func(key string, metric interface{}) {
+		name, tags := wf.DecodeKey(key)
+		if tags[handlerTag] == hs.handler {
+			reporter.GetOrRegisterMetric(hs.prefix+name, metric, tags)
+		}
+	})
+}
+
+// forget unregisters all the metrics of the handler, stopping its timers, once
+// its reporter is closed.
+func (hs *handlerStats) forget() {
+	pipelineStats.Each(func(key string, metric interface{}) {
+		if _, tags := wf.DecodeKey(key); tags[handlerTag] == hs.handler {
+			pipelineStats.Unregister(key)
+			stopMetric(metric)
+		}
+	})
+}
+
+// tags returns the tags of a handler metric, given the extra tags as key
+// value pairs.
+func (hs *handlerStats) tags(pairs ...string) map[string]string {
+	tags := map[string]string{handlerTag: hs.handler}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		tags[pairs[i]] = pairs[i+1]
+	}
+	return tags
+}
+
+// metric returns the handler metric with the given name and extra tags,
+// creating it with newMetric if it doesn't exist yet.
+func (hs *handlerStats) metric(name string, newMetric interface{}, pairs ...string) interface{} {
+	tags := hs.tags(pairs...)
+	metric := pipelineStats.GetOrRegister(wf.EncodeKey(name, tags), newMetric)
+	if hs.reporter != nil {
+		hs.reporter.GetOrRegisterMetric(hs.prefix+name, metric, tags)
+	}
+	return metric
+}
+
+// counter returns the handler counter with the given name and extra tags.
+func (hs *handlerStats) counter(name string, pairs ...string) metrics.Counter {
+	return hs.metric(name, metrics.NewCounter, pairs...).(metrics.Counter)
+}
+
+// timer returns the handler timer with the given name and extra tags.
+func (hs *handlerStats) timer(name string, pairs ...string) metrics.Timer {
+	return hs.metric(name, metrics.NewTimer, pairs...).(metrics.Timer)
+}
+
+// replaceGauge registers a gauge with the given name, replacing the previous
+// one, e.g. one reading from a closed sender, along with the registry view of
+// the reporter to be.
+func (hs *handlerStats) replaceGauge(registry metrics.Registry, name string, gauge metrics.Gauge) {
+	tags := hs.tags()
+	pipelineStats.Unregister(wf.EncodeKey(name, tags))
+	_ = pipelineStats.Register(wf.EncodeKey(name, tags), gauge)
+	registry.Unregister(wf.EncodeKey(hs.prefix+name, tags))
+}
+
+// requestDone records a handled request, and the reason it failed, if it did.
+func (hs *handlerStats) requestDone(start time.Time, reason string) {
+	hs.counter("requests").Inc(1)
+	hs.timer("requests.duration").UpdateSince(start)
+	if reason != "" {
+		hs.counter("requests.errors", reasonTag, reason).Inc(1)
+	}
+}
+
+// dropped records an instance that was dropped for the given reason.
+func (hs *handlerStats) dropped(reason string) {
+	hs.counter("instances.dropped", reasonTag, reason).Inc(1)
+}
+
+// sanitized records a metric name or a tag that had to be changed for
+// Wavefront to accept it.
+func (hs *handlerStats) sanitized(field string) {
+	hs.counter("sanitized", fieldTag, field).Inc(1)
+}
+
+// senderStats returns the counters of a sender.
+func (hs *handlerStats) senderStats() *senderStats {
+	return &senderStats{
+		points:      hs.counter("sender.points"),
+		errors:      hs.counter("sender.errors"),
+		flushErrors: hs.counter("sender.flush.errors"),
+	}
+}
+
+// spoolStats returns the counters of a spool.
+func (hs *handlerStats) spoolStats() *spoolStats {
+	discarded := make(map[string]metrics.Counter)
+	for _, reason := range []string{reasonSpoolFull, reasonSpoolExpired, reasonSpoolCorrupt} {
+		discarded[reason] = hs.counter("spool.discarded", reasonTag, reason)
+	}
+	return &spoolStats{
+		written:   hs.counter("spool.written"),
+		replayed:  hs.counter("spool.replayed"),
+		discarded: discarded,
+	}
+}
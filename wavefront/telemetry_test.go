@@ -0,0 +1,90 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"testing"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+)
+
+// testHandlerStats returns the adapter metrics of the handler with the given
+// configuration.
+func testHandlerStats(cfg *config.Params) *handlerStats {
+	return newHandlerStats(reporterKey(cfg), cfg)
+}
+
+func TestHandlerStats(t *testing.T) {
+	cfg := &config.Params{Source: "telemetry", AdapterMetricsPrefix: "self."}
+	stats := testHandlerStats(cfg)
+	if stats.handler != reporterKey(cfg)[:handlerIDLength] || stats.prefix != "self." {
+		t.Errorf("Unexpected handler stats, got: %v.", stats)
+	}
+
+	// metrics recorded before there's a reporter are registered with it later
+	stats.requestDone(time.Now(), reasonReporterUnavailable)
+	reporter := &fakeReporter{registry: metrics.NewRegistry()}
+	stats.reportTo(reporter)
+	stats.requestDone(time.Now(), "")
+	stats.dropped(reasonUnknownInstance)
+	stats.dropped(reasonUnknownInstance)
+
+	table := []struct {
+		name  string
+		tags  map[string]string
+		count int64
+	}{
+		{"requests", stats.tags(), 2},
+		{"requests.errors", stats.tags(reasonTag, reasonReporterUnavailable), 1},
+		{"instances.dropped", stats.tags(reasonTag, reasonUnknownInstance), 2},
+	}
+
+	for _, entry := range table {
+		counter, ok := pipelineStats.Get(wf.EncodeKey(entry.name, entry.tags)).(metrics.Counter)
+		if !ok || counter.Count() != entry.count {
+			t.Errorf("Counter %s %v failed, got: %v, want: %v.", entry.name, entry.tags, counter, entry.count)
+		}
+		if reported := reporter.GetMetric("self."+entry.name, entry.tags); reported != counter {
+			t.Errorf("Expected counter %s %v to be reported, got: %v.", entry.name, entry.tags, reported)
+		}
+	}
+
+	timer, ok := reporter.GetMetric("self.requests.duration", stats.tags()).(metrics.Timer)
+	if !ok || timer.Count() != 2 {
+		t.Errorf("Expected the requests to be timed, got: %v.", timer)
+	}
+}
+
+func TestSenderStats(t *testing.T) {
+	stats := testHandlerStats(&config.Params{Source: "sender"})
+	sender := newTrackedSender(&fakeSender{}, time.Hour, stats.senderStats(), nil)
+	defer sender.Close()
+
+	_ = sender.SendMetric("metric", 1, 0, "", nil)
+	_ = sender.SendDeltaCounter("counter", 1, "", nil)
+	sender.Sender.(*fakeSender).err = errTest
+	sender.flush()
+
+	if points := stats.counter("sender.points").Count(); points != 2 {
+		t.Errorf("Expected the points sent to be counted, got: %d.", points)
+	}
+	if flushErrors := stats.counter("sender.flush.errors").Count(); flushErrors != 1 {
+		t.Errorf("Expected the failed flushes to be counted, got: %d.", flushErrors)
+	}
+}
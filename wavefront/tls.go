@@ -0,0 +1,229 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"istio.io/istio/pkg/log"
+)
+
+// certCheckInterval is how often the certificate files are checked for changes.
+const certCheckInterval = 10 * time.Second
+
+type (
+	// TLSOptions describes the TLS configuration of the adapter server.
+	TLSOptions struct {
+		// CertFile is the server certificate file in PEM format.
+		CertFile string
+		// KeyFile is the server private key file in PEM format.
+		KeyFile string
+		// CAFile is the CA certificates file in PEM format used to verify client
+		// certificates. Client certificates aren't verified if it's empty.
+		CAFile string
+		// RequireClientCert requires clients to present a certificate signed by
+		// one of the CAs in CAFile.
+		RequireClientCert bool
+		// ClientSANs restricts the accepted client certificates to the ones with
+		// one of the given URI or DNS subject alternative names, if not empty.
+		// It requires RequireClientCert.
+		ClientSANs []string
+	}
+
+	// certStore keeps the server certificate and client CAs loaded from disk,
+	// and reloads them when the files change.
+	certStore struct {
+		options   TLSOptions
+		mu        sync.RWMutex
+		cert      *tls.Certificate
+		clientCAs *x509.CertPool
+		modTimes  map[string]time.Time
+		checkedAt time.Time
+	}
+)
+
+// newCertStore creates a certificate store and loads the certificates.
+func newCertStore(options TLSOptions) (*certStore, error) {
+	if options.CertFile == "" || options.KeyFile == "" {
+		return nil, errors.New("both a certificate and a key file must be supplied for TLS")
+	}
+	if options.RequireClientCert && options.CAFile == "" {
+		return nil, errors.New("a CA file must be supplied to require client certificates")
+	}
+	if len(options.ClientSANs) > 0 && !options.RequireClientCert {
+		return nil, errors.New("client certificates must be required to restrict their subject alternative names")
+	}
+
+	cs := &certStore{options: options}
+	if err := cs.load(); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// files returns the files the store loads from.
+func (cs *certStore) files() []string {
+	files := []string{cs.options.CertFile, cs.options.KeyFile}
+	if cs.options.CAFile != "" {
+		files = append(files, cs.options.CAFile)
+	}
+	return files
+}
+
+// load reads the certificate, key and CA files.
+func (cs *certStore) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range cs.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("couldn't read %s: %v", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(cs.options.CertFile, cs.options.KeyFile)
+	if err != nil {
+		return fmt.Errorf("couldn't load the server certificate: %v", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if cs.options.CAFile != "" {
+		pem, err := ioutil.ReadFile(cs.options.CAFile)
+		if err != nil {
+			return fmt.Errorf("couldn't read %s: %v", cs.options.CAFile, err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no CA certificates were found in %s", cs.options.CAFile)
+		}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.cert = &cert
+	cs.clientCAs = clientCAs
+	cs.modTimes = modTimes
+	cs.checkedAt = time.Now()
+	return nil
+}
+
+// changed reports whether any of the files changed since they were loaded.
+func (cs *certStore) changed() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	for _, file := range cs.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			// the file may be in the middle of a rotation; try again later
+			return false
+		}
+		if !info.ModTime().Equal(cs.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadIfChanged reloads the certificates if the files changed, at most once
+// per certCheckInterval. The previous certificates are kept on errors.
+func (cs *certStore) reloadIfChanged() {
+	cs.mu.Lock()
+	due := time.Since(cs.checkedAt) >= certCheckInterval
+	if due {
+		cs.checkedAt = time.Now()
+	}
+	cs.mu.Unlock()
+
+	if due && cs.changed() {
+		if err := cs.load(); err != nil {
+			log.Errorf("couldn't reload the TLS certificates, keeping the previous ones, err: %v", err)
+			return
+		}
+		log.Infof("reloaded the TLS certificates")
+	}
+}
+
+// verifyClientSAN checks that the client certificate has one of the accepted
+// subject alternative names. Clients without a certificate are refused when
+// names are accepted.
+func (cs *certStore) verifyClientSAN(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(cs.options.ClientSANs) == 0 {
+		return nil
+	}
+	if len(rawCerts) == 0 {
+		return errors.New("a client certificate is required")
+	}
+
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	names = append(names, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, name := range names {
+		for _, accepted := range cs.options.ClientSANs {
+			if name == accepted {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("client certificate names %v aren't accepted", names)
+}
+
+// configForClient returns the TLS configuration for a new client connection.
+func (cs *certStore) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	cs.reloadIfChanged()
+
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	cfg := &tls.Config{
+		Certificates:          []tls.Certificate{*cs.cert},
+		ClientCAs:             cs.clientCAs,
+		ClientAuth:            tls.NoClientCert,
+		MinVersion:            tls.VersionTLS12,
+		NextProtos:            []string{"h2"},
+		VerifyPeerCertificate: cs.verifyClientSAN,
+	}
+	if cs.clientCAs != nil {
+		if cs.options.RequireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+	return cfg, nil
+}
+
+// tlsConfig returns a server TLS configuration that picks up rotated certificates.
+func (cs *certStore) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: cs.configForClient,
+	}
+}
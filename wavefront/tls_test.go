@@ -0,0 +1,161 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert writes a self-signed certificate and its key to the given files,
+// and returns the DER encoded certificate.
+func writeCert(t *testing.T, certFile, keyFile string, serial int64, uri string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "wavefront"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if uri != "" {
+		u, _ := url.Parse(uri)
+		template.URIs = []*url.URL{u}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	if err := ioutil.WriteFile(certFile, certPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, keyPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestCertStoreOptions(t *testing.T) {
+	table := []TLSOptions{
+		{},
+		{CertFile: "cert.pem"},
+		{CertFile: "cert.pem", KeyFile: "key.pem", RequireClientCert: true},
+		{CertFile: "cert.pem", KeyFile: "key.pem", ClientSANs: []string{"spiffe://cluster.local/ns/istio-system/sa/istio-mixer-service-account"}},
+		{CertFile: "cert.pem", KeyFile: "key.pem", CAFile: "ca.pem", ClientSANs: []string{"spiffe://cluster.local/ns/istio-system/sa/istio-mixer-service-account"}},
+		{CertFile: "missing.pem", KeyFile: "missing.pem"},
+	}
+
+	for _, entry := range table {
+		if _, err := newCertStore(entry); err == nil {
+			t.Errorf("Expected an error for %v.", entry)
+		}
+	}
+}
+
+func TestCertStoreReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeCert(t, certFile, keyFile, 1, "")
+
+	cs, err := newCertStore(TLSOptions{CertFile: certFile, KeyFile: keyFile, CAFile: certFile, RequireClientCert: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _ := cs.configForClient(&tls.ClientHelloInfo{})
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("Expected client certificates to be required, got: %v.", cfg.ClientAuth)
+	}
+
+	// rotate the certificate and make the next handshake check for changes
+	rotated := writeCert(t, certFile, keyFile, 2, "")
+	future := time.Now().Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cs.checkedAt = time.Time{}
+
+	cfg, _ = cs.configForClient(&tls.ClientHelloInfo{})
+	if got := cfg.Certificates[0].Certificate[0]; string(got) != string(rotated) {
+		t.Errorf("Expected the rotated certificate to be served.")
+	}
+}
+
+func TestVerifyClientSAN(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mixer := "spiffe://cluster.local/ns/istio-system/sa/istio-mixer-service-account"
+	mixerCert := writeCert(t, filepath.Join(dir, "mixer.pem"), filepath.Join(dir, "mixer-key.pem"), 1, mixer)
+	otherCert := writeCert(t, filepath.Join(dir, "other.pem"), filepath.Join(dir, "other-key.pem"), 2, "spiffe://cluster.local/ns/default/sa/default")
+
+	table := []struct {
+		sans  []string
+		certs [][]byte
+		valid bool
+	}{
+		{nil, [][]byte{otherCert}, true},
+		{nil, nil, true},
+		{[]string{mixer}, [][]byte{mixerCert}, true},
+		{[]string{mixer}, [][]byte{otherCert}, false},
+		{[]string{mixer}, nil, false},
+	}
+
+	for _, entry := range table {
+		cs := &certStore{options: TLSOptions{ClientSANs: entry.sans}}
+		if err := cs.verifyClientSAN(entry.certs, nil); (err == nil) != entry.valid {
+			t.Errorf("Verification failed for %v, got: %v, want valid: %v.", entry.sans, err, entry.valid)
+		}
+	}
+}
@@ -24,17 +24,27 @@ package wavefront
 import (
 	"context"
 	"fmt"
+	"math"
 	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/gogo/protobuf/types"
 	metrics "github.com/rcrowley/go-metrics"
 	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
 	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
 	"github.com/wavefronthq/wavefront-sdk-go/application"
+	wfhistogram "github.com/wavefronthq/wavefront-sdk-go/histogram"
 	"github.com/wavefronthq/wavefront-sdk-go/senders"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	"istio.io/api/mixer/adapter/model/v1beta1"
 	policy "istio.io/api/policy/v1beta1"
 	"istio.io/istio/mixer/template/metric"
@@ -47,49 +57,110 @@ type (
 		Addr() string
 		Close() error
 		Run(shutdown chan error)
+		Shutdown(ctx context.Context) error
+	}
+
+	// Option configures the adapter server.
+	Option func(*serverOptions)
+
+	// serverOptions holds the adapter server configuration.
+	serverOptions struct {
+		tls         *TLSOptions
+		config      *config.Params
+		healthAddr  string
+		metricsAddr string
 	}
 
 	// WavefrontAdapter supports metric template.
 	WavefrontAdapter struct {
-		listener net.Listener
-		server   *grpc.Server
-		reporter wf.WavefrontMetricsReporter
+		listener   net.Listener
+		server     *grpc.Server
+		reporters  *reporterCache
+		config     *config.Params
+		health     *httpServer
+		metrics    *httpServer
+		grpcHealth *health.Server
+		stopping   int32
+		// logLevel is the log level set last, guarded by logMu
+		logLevel string
+		logMu    sync.Mutex
+		done     chan struct{}
+		stopOnce sync.Once
 	}
 )
 
+// healthCheckInterval is how often the gRPC health status is updated.
+const healthCheckInterval = 5 * time.Second
+
 // ensure that WavefrontAdapter implements the HandleMetricServiceServer interface.
 var _ metric.HandleMetricServiceServer = &WavefrontAdapter{}
 
-// createWavefrontReporter creates a reporter that periodically flushes metrics to Wavefront.
-func (wa *WavefrontAdapter) createWavefrontReporter(cfg *config.Params) {
+// createWavefrontReporter creates a reporter that periodically flushes the
+// metrics of the given registry to Wavefront, along with its sender.
+func (wa *WavefrontAdapter) createWavefrontReporter(key string, cfg *config.Params, registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
 	var sender senders.Sender
-	flushInterval := int(cfg.FlushInterval.Seconds())
+	var err error
+	interval := config.FlushInterval(cfg)
+	flushInterval := int(interval.Seconds())
+	stats := newHandlerStats(key, cfg)
+	var sp *spool
+	if cfg.Spool != nil {
+		if sp, err = openSpool(spoolDir(key, cfg), cfg.Spool, stats.spoolStats()); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if direct := cfg.GetDirect(); direct != nil {
-		sender = createDirectSender(direct, flushInterval)
+		sender, err = createDirectSender(direct, flushInterval)
 	} else if proxy := cfg.GetProxy(); proxy != nil {
-		sender = createProxySender(proxy, flushInterval)
-	}
-
-	if sender != nil {
-		wa.reporter = wf.NewReporter(
-			sender,
-			application.New("wavefront-istio-adapter", "wavefront-istio-adapter"),
-			wf.Source(cfg.Source),
-			wf.Prefix(cfg.Prefix),
-			wf.LogErrors(true),
-			wf.Interval(time.Minute*1),
-		)
+		sender, err = createProxySender(proxy, flushInterval)
 	} else {
-		log.Fatalf("Wavefront sender is not initialized.")
+		err = config.NoCredentialsError
 	}
+	if err != nil {
+		if sp != nil {
+			sp.close()
+		}
+		return nil, nil, err
+	}
+	tracked := newTrackedSender(sender, interval, stats.senderStats(), sp)
+	stats.replaceGauge(registry, "sender.failures", metrics.NewFunctionalGauge(tracked.GetFailureCount))
+	stats.replaceGauge(registry, "sender.queue.size.approx", metrics.NewFunctionalGauge(tracked.approxQueueSize))
+	if pool, ok := sender.(*proxyPool); ok {
+		stats.replaceGauge(registry, "sender.proxies.healthy", metrics.NewFunctionalGauge(pool.healthyCount))
+	}
+	if sp != nil {
+		stats.replaceGauge(registry, "spool.points", metrics.NewFunctionalGauge(sp.depth))
+		stats.replaceGauge(registry, "spool.bytes", metrics.NewFunctionalGauge(sp.bytes))
+		stats.replaceGauge(registry, "spool.age.seconds", metrics.NewFunctionalGauge(sp.age))
+	}
+
+	reporter := wf.NewReporter(
+		tracked,
+		application.New("wavefront-istio-adapter", "wavefront-istio-adapter"),
+		wf.Source(cfg.Source),
+		wf.Prefix(cfg.Prefix),
+		wf.LogErrors(true),
+		wf.Interval(interval),
+		wf.CustomRegistry(registry),
+	)
 
 	hostTags := map[string]string{"source": cfg.Source}
-	createSystemStatsReporter(hostTags)
+	createSystemStatsReporter(registry, stats.prefix, hostTags)
+	stats.reportTo(reporter)
+	return reporter, tracked, nil
 }
 
-// setLogLevel sets the adapter log level.
+// setLogLevel sets the adapter log level, if it changed.
 func (wa *WavefrontAdapter) setLogLevel(cfg *config.Params) {
 	if logs := cfg.GetLogs(); logs != nil {
+		wa.logMu.Lock()
+		defer wa.logMu.Unlock()
+		if logs.Level == wa.logLevel {
+			return
+		}
+		wa.logLevel = logs.Level
+
 		var level log.Level
 		switch logs.Level {
 		case "error":
@@ -115,75 +186,72 @@ func (wa *WavefrontAdapter) setLogLevel(cfg *config.Params) {
 	}
 }
 
-// verifyAndInitReporter checks if the Wavefront reporter is initialized, and if
-// not, initializes it.
-func (wa *WavefrontAdapter) verifyAndInitReporter(cfg *config.Params) {
-	if wa.reporter == nil {
-		log.Infof("trying to init wavefront reporter, config: %s", cfg.String())
-		wa.setLogLevel(cfg)
+// verifyAndInitReporter returns the Wavefront reporter for the given
+// configuration, initializing it if it doesn't exist yet. An invalid
+// configuration fails with InvalidArgument. Failed initializations fail with
+// Unavailable, and are retried with backoff on later calls.
+func (wa *WavefrontAdapter) verifyAndInitReporter(key string, cfg *config.Params) (*handlerReporter, error) {
+	if err := validateReporterConfig(cfg); err != nil {
+		log.Errorf("invalid wavefront reporter configuration, err: %s, config: %s", err.Error(), cfg.String())
+		return nil, status.Errorf(codes.InvalidArgument, "invalid wavefront reporter configuration: %v", err)
+	}
 
-		if err := config.ValidateCredentials(cfg); err != nil {
+	hr, err := wa.reporters.getOrCreate(key, cfg, func(registry metrics.Registry) (wf.WavefrontMetricsReporter, *trackedSender, error) {
+		log.Infof("trying to init wavefront reporter, config: %s", cfg.String())
+		reporter, sender, err := wa.createWavefrontReporter(key, cfg, registry)
+		if err != nil {
 			log.Errorf("failed to create wavefront reporter, err: %s, config: %s", err.Error(), cfg.String())
-		} else {
-			wa.createWavefrontReporter(cfg)
-			log.Infof("wavefront reporter successfully initialized, config: %s", cfg.String())
+			return nil, nil, err
 		}
+		log.Infof("wavefront reporter of handler %s successfully initialized, config: %s", handlerID(key), cfg.String())
+		return reporter, sender, nil
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "wavefront reporter is not initialized: %v", err)
 	}
+	return hr, nil
 }
 
 // creates wavefront direct sender
-func createDirectSender(direct *config.Params_WavefrontDirect, flushInterval int) senders.Sender {
+func createDirectSender(direct *config.Params_WavefrontDirect, flushInterval int) (senders.Sender, error) {
 	directCfg := &senders.DirectConfiguration{
 		Server:               direct.Server,
 		Token:                direct.Token,
 		FlushIntervalSeconds: flushInterval,
 		BatchSize:            10000,
-		MaxBufferSize:        50000,
+		MaxBufferSize:        config.MaxBufferSize(direct),
 	}
 	sender, err := senders.NewDirectSender(directCfg)
 	if err != nil {
-		log.Fatalf("Error creating direct sender: %v", err)
-		return nil
+		return nil, fmt.Errorf("error creating direct sender: %v", err)
 	}
-	return sender
+	return sender, nil
 }
 
-// creates wavefront proxy sender
-func createProxySender(proxy *config.Params_WavefrontProxy, flushInterval int) senders.Sender {
-	addr, err := net.ResolveTCPAddr("tcp", proxy.Address)
-	if err != nil {
-		log.Fatalf("Cannot resolve proxy address %v", err)
-		return nil
+// creates wavefront proxy sender, spreading the data over all the proxies found
+func createProxySender(proxy *config.Params_WavefrontProxy, flushInterval int) (senders.Sender, error) {
+	resolve := func() ([]proxyEndpoint, error) {
+		return resolveProxyEndpoints(config.ProxyAddresses(proxy))
 	}
 
-	// extract proxy ip and port from address
-	proxyInfo := strings.Split(addr.String(), ":")
-
-	// address must be in the form <proxyhost:port>
-	if len(proxyInfo) != 2 {
-		log.Fatalf("Proxy address and/or port number is missing.")
-		return nil
-	}
-
-	// numeric port number expected
-	portNum, err := strconv.Atoi(proxyInfo[1])
-	if err != nil {
-		log.Fatalf("Invalid port number %v", err)
-		return nil
-	}
-
-	proxyCfg := &senders.ProxyConfiguration{
-		Host:                 proxyInfo[0],
-		MetricsPort:          portNum,
-		FlushIntervalSeconds: flushInterval,
-	}
+	pool, err := newProxyPool(proxy, resolve, func(endpoint proxyEndpoint) (senders.Sender, error) {
+		proxyCfg := &senders.ProxyConfiguration{
+			Host:                 endpoint.dialHost(),
+			MetricsPort:          endpoint.port,
+			DistributionPort:     int(proxy.DistributionPort),
+			FlushIntervalSeconds: flushInterval,
+		}
 
-	sender, err := senders.NewProxySender(proxyCfg)
+		sender, err := senders.NewProxySender(proxyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("error creating proxy sender: %v", err)
+		}
+		return sender, nil
+	})
 	if err != nil {
-		log.Fatalf("Error creating proxy sender: %v", err)
-		return nil
+		return nil, err
 	}
-	return sender
+	return pool, nil
 }
 
 // createMetricMap creates a map of metric names and the corresponding MetricInfo objects.
@@ -214,13 +282,76 @@ func translateToInt64(value interface{}) (int64, error) {
 		return v, nil
 	case float64:
 		return int64(v), nil
-	case *policy.Duration:
-		return int64(v.GetValue().GetNanos()), nil
 	default:
 		return 0, fmt.Errorf("couldn't convert %s to int64", value)
 	}
 }
 
+// translateToCount converts a given number to a count, that is an int64, or
+// returns an error if it has a fractional part, which a count would lose.
+func translateToCount(value interface{}) (int64, error) {
+	if v, ok := value.(float64); ok && v != math.Trunc(v) {
+		return 0, fmt.Errorf("couldn't count %v, which isn't an integer", v)
+	}
+	return translateToInt64(value)
+}
+
+// translateDuration converts a duration to a number in the given unit or
+// returns an error.
+func translateDuration(d *policy.Duration, unit config.Params_MetricInfo_DurationUnit) (float64, error) {
+	duration, err := types.DurationFromProto(d.GetValue())
+	if err != nil {
+		return 0, fmt.Errorf("couldn't convert duration %v: %v", d, err)
+	}
+
+	switch unit {
+	case config.MICROSECONDS:
+		return float64(duration) / float64(time.Microsecond), nil
+	case config.MILLISECONDS:
+		return float64(duration) / float64(time.Millisecond), nil
+	case config.SECONDS:
+		return duration.Seconds(), nil
+	default:
+		return float64(duration.Nanoseconds()), nil
+	}
+}
+
+// convertValue converts a decoded metric value as configured for the given
+// metric, so that it can be translated to a number. Durations are converted to
+// the duration unit, booleans to 0 or 1 and strings through the value map or
+// by parsing them, if enabled. Other values are returned as is.
+func convertValue(m *config.Params_MetricInfo, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *policy.Duration:
+		return translateDuration(v, m.DurationUnit)
+	case bool:
+		if !m.ConvertBools {
+			return value, nil
+		}
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case string:
+		if mapped, ok := m.ValueMap[v]; ok {
+			return mapped, nil
+		}
+		if !m.ParseStrings {
+			return value, nil
+		}
+		if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %q as a number", v)
+		}
+		return f, nil
+	default:
+		return value, nil
+	}
+}
+
 // translateSample translates a config.Sample instance to a metrics.Sample instance.
 func translateSample(s *config.Params_MetricInfo_Sample) metrics.Sample {
 	if def := s.GetExpDecay(); def != nil {
@@ -231,37 +362,184 @@ func translateSample(s *config.Params_MetricInfo_Sample) metrics.Sample {
 	return nil
 }
 
+// translateGranularity translates a config granularity to a Wavefront histogram granularity.
+func translateGranularity(g config.Params_MetricInfo_Granularity) wfhistogram.Granularity {
+	switch g {
+	case config.HOUR:
+		return wfhistogram.HOUR
+	case config.DAY:
+		return wfhistogram.DAY
+	default:
+		return wfhistogram.MINUTE
+	}
+}
+
+// newTimer creates a timer with the given sample, or with the default
+// exponentially decaying sample if there's none.
+func newTimer(s *config.Params_MetricInfo_Sample) metrics.Timer {
+	if s == nil {
+		return metrics.NewTimer()
+	}
+	return metrics.NewCustomTimer(metrics.NewHistogram(translateSample(s)), metrics.NewMeter())
+}
+
+// getOrRegister returns the metric of the given reporter with the given name
+// and tags, registering a new one created with newMetric if there's none yet.
+// Unlike GetOrRegisterMetric, it only creates a metric when needed, which
+// matters for the meters that are ticked until they're stopped.
+// A registered metric of another kind, left by a configuration that gave the
+// metric another type, is replaced. It returns nil if the metric couldn't be
+// replaced, e.g. because a request with the previous configuration registered
+// it again in the meantime.
+func getOrRegister(reporter wf.WavefrontMetricsReporter, name string, tags map[string]string, matches func(interface{}) bool, newMetric func() interface{}) interface{} {
+	existing := reporter.GetMetric(name, tags)
+	if existing != nil && matches(existing) {
+		return existing
+	}
+	if existing != nil {
+		log.Infof("metric %s changed type, replacing it, tags: %v", name, tags)
+		reporter.UnregisterMetric(name, tags)
+		stopMetric(existing)
+	}
+	metric := newMetric()
+	if err := reporter.RegisterMetric(name, metric, tags); err != nil {
+		// another request registered it in the meantime
+		stopMetric(metric)
+		if existing = reporter.GetMetric(name, tags); existing != nil && matches(existing) {
+			return existing
+		}
+		return nil
+	}
+	return metric
+}
+
+// stopMetric stops a metric that is updated in the background until stopped,
+// such as a meter or a timer.
+func stopMetric(metric interface{}) {
+	if stoppable, ok := metric.(interface{ Stop() }); ok {
+		stoppable.Stop()
+	}
+}
+
+// The kinds of metrics each metric type is recorded with.
+func isGauge(m interface{}) bool {
+	_, ok := m.(metrics.GaugeFloat64)
+	return ok
+}
+
+func isCounter(m interface{}) bool {
+	_, ok := m.(metrics.Counter)
+	return ok
+}
+
+func isHistogram(m interface{}) bool {
+	_, ok := m.(metrics.Histogram)
+	return ok && !isWavefrontHistogram(m)
+}
+
+func isWavefrontHistogram(m interface{}) bool {
+	_, ok := m.(interface {
+		metrics.Histogram
+		Granularity() wfhistogram.Granularity
+	})
+	return ok
+}
+
+func isTimer(m interface{}) bool {
+	_, ok := m.(metrics.Timer)
+	return ok
+}
+
+func isMeter(m interface{}) bool {
+	_, ok := m.(metrics.Meter)
+	return ok
+}
+
 // writeMetrics extracts metric information from metric.InstanceMsgs and writes
-// it to the Wavefront metric registry.
-func (wa *WavefrontAdapter) writeMetrics(cfg *config.Params, insts []*metric.InstanceMsg) {
+// it to the registry of the given Wavefront reporter, within its series limits.
+// The dimension values are rewritten with the given rewriters.
+func (wa *WavefrontAdapter) writeMetrics(hr *handlerReporter, cfg *config.Params, rewriters []*config.TagRewriter, insts []*metric.InstanceMsg, stats *handlerStats) {
+	defer stats.timer("instances.duration").UpdateSince(time.Now())
+	stats.counter("instances").Inc(int64(len(insts)))
+
+	reporter := hr.reporter
 	metricMap := createMetricMap(cfg.Metrics)
 	for _, inst := range insts {
 		metric, metricFound := metricMap[inst.Name]
 		if !metricFound {
 			log.Warnf("couldn't find metric for instance %s in configuration %s, ignoring", inst.Name, cfg.String())
+			stats.dropped(reasonUnknownInstance)
 			continue
 		}
 
-		metricName := config.MetricName(metric)
-		value := decodeValue(inst.Value.GetValue())
-		tags := decodeTags(inst.Dimensions)
+		metricName := sanitizeMetricName(config.MetricName(metric), stats)
+		value, err := convertValue(metric, decodeValue(inst.Value.GetValue()))
+		if err != nil {
+			log.Warnf("couldn't convert metric value: %s, err: %v", metricName, err)
+			stats.dropped(reasonConvertFailure)
+			continue
+		}
+		tags := applyTagRules(mergeTagRules(cfg.Tags, metric.Tags), decodeTags(inst.Dimensions, cfg.TagFormats, rewriters))
+		tags = sanitizeTags(tags, stats)
+		if !hr.series.admit(cfg, metric, metricName, tags) {
+			if metric.Overflow != config.COLLAPSE {
+				stats.dropped(reasonSeriesLimit)
+				continue
+			}
+			tags = overflowTags(tags)
+			hr.series.touch(metric, metricName, tags)
+			stats.counter("instances.collapsed").Inc(1)
+		}
 
 		switch metric.Type {
-		case config.GAUGE, config.COUNTER:
+		case config.GAUGE:
 			if float64Val, err := translateToFloat64(value); err != nil {
 				log.Warnf("couldn't translate metric value: %s %v, err: %v", metricName, value, err)
+				stats.dropped(reasonTranslateFailure)
 			} else {
-				gauge := wa.reporter.GetOrRegisterMetric(metricName, metrics.NewGaugeFloat64(), tags).(metrics.GaugeFloat64)
+				gauge, ok := getOrRegister(reporter, metricName, tags, isGauge, func() interface{} {
+					if metric.Aggregation == config.LAST {
+						return metrics.NewGaugeFloat64()
+					}
+					return newAggregatingGauge(metric.Aggregation)
+				}).(metrics.GaugeFloat64)
+				if !ok {
+					stats.dropped(reasonTypeMismatch)
+					continue
+				}
 				gauge.Update(float64Val)
 				log.Debugf("updated gauge metric %s with %v, tags: %v", metricName, float64Val, tags)
 			}
 
+		case config.COUNTER:
+			if int64Val, err := translateToCount(value); err != nil {
+				log.Warnf("couldn't translate metric value: %s %v, err: %v", metricName, value, err)
+				stats.dropped(reasonTranslateFailure)
+			} else {
+				counter, ok := getOrRegister(reporter, metricName, tags, isCounter, func() interface{} {
+					return metrics.NewCounter()
+				}).(metrics.Counter)
+				if !ok {
+					stats.dropped(reasonTypeMismatch)
+					continue
+				}
+				counter.Inc(int64Val)
+				log.Debugf("updated counter metric %s with %v, tags: %v", metricName, int64Val, tags)
+			}
+
 		case config.DELTA_COUNTER:
-			if int64Val, err := translateToInt64(value); err != nil {
+			if int64Val, err := translateToCount(value); err != nil {
 				log.Warnf("couldn't translate metric value: %s %v, err: %v", metricName, value, err)
+				stats.dropped(reasonTranslateFailure)
 			} else {
 				deltaMetricName := wf.DeltaCounterName(metricName)
-				counter := wa.reporter.GetOrRegisterMetric(deltaMetricName, metrics.NewCounter(), tags).(metrics.Counter)
+				counter, ok := getOrRegister(reporter, deltaMetricName, tags, isCounter, func() interface{} {
+					return metrics.NewCounter()
+				}).(metrics.Counter)
+				if !ok {
+					stats.dropped(reasonTypeMismatch)
+					continue
+				}
 				counter.Inc(int64Val)
 				log.Debugf("updated delta counter metric %s with %v, tags: %v", deltaMetricName, int64Val, tags)
 			}
@@ -269,61 +547,186 @@ func (wa *WavefrontAdapter) writeMetrics(cfg *config.Params, insts []*metric.Ins
 		case config.HISTOGRAM:
 			if int64Val, err := translateToInt64(value); err != nil {
 				log.Warnf("couldn't translate metric value: %s %v, err: %v", metricName, value, err)
+				stats.dropped(reasonTranslateFailure)
 			} else {
-				histogram := wf.GetMetric(metricName, tags)
-				if histogram == nil {
-					sample := translateSample(metric.Sample)
-					histogram = metrics.NewHistogram(sample)
-					wa.reporter.RegisterMetric(metricName, histogram, tags)
+				histogram, ok := getOrRegister(reporter, metricName, tags, isHistogram, func() interface{} {
+					return metrics.NewHistogram(translateSample(metric.Sample))
+				}).(metrics.Histogram)
+				if !ok {
+					stats.dropped(reasonTypeMismatch)
+					continue
 				}
-				histogram.(metrics.Histogram).Update(int64Val)
+				histogram.Update(int64Val)
 				log.Debugf("updated histogram metric %s with %v, tags: %v", metricName, int64Val, tags)
 			}
 
+		case config.WAVEFRONT_HISTOGRAM:
+			if int64Val, err := translateToInt64(value); err != nil {
+				log.Warnf("couldn't translate metric value: %s %v, err: %v", metricName, value, err)
+				stats.dropped(reasonTranslateFailure)
+			} else {
+				histogram, ok := getOrRegister(reporter, metricName, tags, isWavefrontHistogram, func() interface{} {
+					return wf.NewHistogram(wfhistogram.GranularityOption(translateGranularity(metric.Granularity)))
+				}).(metrics.Histogram)
+				if !ok {
+					stats.dropped(reasonTypeMismatch)
+					continue
+				}
+				histogram.Update(int64Val)
+				log.Debugf("updated wavefront histogram metric %s with %v, tags: %v", metricName, int64Val, tags)
+			}
+
+		case config.TIMER:
+			if int64Val, err := translateToInt64(value); err != nil {
+				log.Warnf("couldn't translate metric value: %s %v, err: %v", metricName, value, err)
+				stats.dropped(reasonTranslateFailure)
+			} else {
+				timer, ok := getOrRegister(reporter, metricName, tags, isTimer, func() interface{} {
+					return newTimer(metric.Sample)
+				}).(metrics.Timer)
+				if !ok {
+					stats.dropped(reasonTypeMismatch)
+					continue
+				}
+				// the value is in the duration unit of the metric, and the
+				// reporter reports timer values in nanoseconds as is
+				timer.Update(time.Duration(int64Val))
+				log.Debugf("updated timer metric %s with %v, tags: %v", metricName, int64Val, tags)
+			}
+
+		case config.METER:
+			if int64Val, err := translateToInt64(value); err != nil {
+				log.Warnf("couldn't translate metric value: %s %v, err: %v", metricName, value, err)
+				stats.dropped(reasonTranslateFailure)
+			} else {
+				meter, ok := getOrRegister(reporter, metricName, tags, isMeter, func() interface{} {
+					return metrics.NewMeter()
+				}).(metrics.Meter)
+				if !ok {
+					stats.dropped(reasonTypeMismatch)
+					continue
+				}
+				meter.Mark(int64Val)
+				log.Debugf("updated meter metric %s with %v, tags: %v", metricName, int64Val, tags)
+			}
+
 		default:
 			log.Warnf("couldn't handle metric %s with value %s, tags: %v", metricName, value, tags)
+			stats.dropped(reasonUnsupportedType)
 		}
 	}
 }
 
 // HandleMetric records metric entries.
 func (wa *WavefrontAdapter) HandleMetric(ctx context.Context, r *metric.HandleMetricRequest) (*v1beta1.ReportResult, error) {
+	start := time.Now()
 	log.Infof("received request %v\n", *r)
 
 	// unmarshal configuration
 	cfg := &config.Params{}
+	var raw []byte
 	if r.AdapterConfig != nil {
-		if err := cfg.Unmarshal(r.AdapterConfig.Value); err != nil {
+		raw = r.AdapterConfig.Value
+		if err := cfg.Unmarshal(raw); err != nil {
 			log.Errorf("error unmarshalling adapter config: %v", err)
+			unknownHandlerStats().requestDone(start, reasonInvalidConfig)
 			return nil, err
 		}
+	} else if wa.config != nil {
+		cfg = wa.config
 	}
+	key := wa.reporters.key(raw, cfg)
+	stats := newHandlerStats(key, cfg)
+	wa.setLogLevel(cfg)
 
-	// init the Wavefront reporter if not initialized already
-	wa.verifyAndInitReporter(cfg)
+	// get the Wavefront reporter for this configuration, initializing it if needed
+	hr, err := wa.verifyAndInitReporter(key, cfg)
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			stats.requestDone(start, reasonInvalidConfig)
+		} else {
+			stats.requestDone(start, reasonReporterUnavailable)
+		}
+		return nil, err
+	}
+	stats.attach(hr.reporter)
 
 	// validate the metrics configuration
 	if err := config.ValidateMetrics(cfg); err != nil {
 		log.Errorf("error validating metrics config: %v %s", err, cfg.String())
+		stats.requestDone(start, reasonInvalidMetrics)
+		return nil, err
+	}
+
+	// validate and compile the tag rewrites
+	rewriters, err := config.ValidateTagRewrites(cfg)
+	if err != nil {
+		log.Errorf("error validating tag rewrites config: %v %s", err, cfg.String())
+		stats.requestDone(start, reasonInvalidTagRewrites)
 		return nil, err
 	}
 
 	// write metrics
-	wa.writeMetrics(cfg, r.Instances)
+	wa.writeMetrics(hr, cfg, rewriters, r.Instances, stats)
+	stats.requestDone(start, "")
 
 	log.Infof("metrics were processed successfully!")
 	return &v1beta1.ReportResult{}, nil
 }
 
-// decodeTags converts dimensions to a map of tags.
-func decodeTags(dimensions map[string]*policy.Value) map[string]string {
+// decodeTags converts dimensions to a map of tags, formatting their values in
+// the given formats by dimension, and rewriting them with the given rewriters
+// in order.
+func decodeTags(dimensions map[string]*policy.Value, formats map[string]config.Params_TagFormat, rewriters []*config.TagRewriter) map[string]string {
 	tags := make(map[string]string, len(dimensions))
 	for i, d := range dimensions {
-		tags[i] = fmt.Sprintf("%v", decodeValue(d.GetValue()))
+		tags[i] = formatTagValue(decodeValue(d.GetValue()), formats[i])
+	}
+	for _, r := range rewriters {
+		if value, ok := tags[r.Tag]; ok {
+			tags[r.Tag] = r.Rewrite(value)
+		}
 	}
 	return tags
 }
 
+// formatTagValue formats a decoded dimension value as a tag value, in the
+// given format if it applies to the type of the value. By default, IP
+// addresses are formatted in dotted or colon notation, timestamps in RFC 3339
+// format in UTC, and durations as Go duration strings.
+func formatTagValue(value interface{}, format config.Params_TagFormat) string {
+	switch v := value.(type) {
+	case *policy.IPAddress:
+		ip := net.IP(v.Value)
+		if ip4 := ip.To4(); ip4 != nil && format == config.COLON_NOTATION {
+			return fmt.Sprintf("::ffff:%x:%x", uint16(ip4[0])<<8|uint16(ip4[1]), uint16(ip4[2])<<8|uint16(ip4[3]))
+		}
+		return ip.String()
+	case *policy.TimeStamp:
+		if ts, err := types.TimestampFromProto(v.Value); err == nil {
+			switch format {
+			case config.EPOCH_SECONDS:
+				return strconv.FormatInt(ts.Unix(), 10)
+			case config.EPOCH_MILLISECONDS:
+				return strconv.FormatInt(ts.UnixNano()/int64(time.Millisecond), 10)
+			default:
+				return ts.UTC().Format(time.RFC3339)
+			}
+		}
+	case *policy.Duration:
+		if d, err := types.DurationFromProto(v.Value); err == nil {
+			return d.String()
+		}
+	case *policy.EmailAddress:
+		return v.Value
+	case *policy.DNSName:
+		return v.Value
+	case *policy.Uri:
+		return v.Value
+	}
+	return fmt.Sprintf("%v", value)
+}
+
 // decodeValue decodes a policy.Value instance.
 func decodeValue(in interface{}) interface{} {
 	switch t := in.(type) {
@@ -359,41 +762,236 @@ func (wa *WavefrontAdapter) Addr() string {
 
 // Run starts the server run.
 func (wa *WavefrontAdapter) Run(shutdown chan error) {
+	if wa.health != nil {
+		go wa.health.run()
+	}
+	if wa.metrics != nil {
+		go wa.metrics.run()
+	}
 	shutdown <- wa.server.Serve(wa.listener)
 }
 
+// ready returns an error describing why the adapter isn't ready, if it isn't.
+// The adapter is ready when all the reporters for the handler configurations
+// received so far are initialized and flushing successfully.
+func (wa *WavefrontAdapter) ready() error {
+	if atomic.LoadInt32(&wa.stopping) != 0 {
+		return fmt.Errorf("shutting down")
+	}
+	return wa.reporters.status()
+}
+
+// updateHealth sets the gRPC health status according to readiness.
+func (wa *WavefrontAdapter) updateHealth() {
+	if err := wa.ready(); err != nil {
+		log.Debugf("adapter isn't ready: %v", err)
+		wa.grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+	wa.grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+}
+
+// watchHealth periodically updates the gRPC health status until the adapter stops.
+func (wa *WavefrontAdapter) watchHealth() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			wa.updateHealth()
+		case <-wa.done:
+			return
+		}
+	}
+}
+
+// stop marks the adapter as stopping and stops watching its health.
+func (wa *WavefrontAdapter) stop() {
+	wa.stopOnce.Do(func() {
+		atomic.StoreInt32(&wa.stopping, 1)
+		close(wa.done)
+		wa.grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	})
+}
+
 // Close gracefully shuts down the server; used for testing.
 func (wa *WavefrontAdapter) Close() error {
+	wa.stop()
 	if wa.server != nil {
 		wa.server.GracefulStop()
 	}
 	if wa.listener != nil {
 		_ = wa.listener.Close()
 	}
-	if wa.reporter != nil {
-		wa.reporter.Close()
+	if wa.reporters != nil {
+		wa.reporters.close()
+	}
+	if wa.health != nil {
+		wa.health.close()
+	}
+	if wa.metrics != nil {
+		wa.metrics.close()
 	}
 
 	return nil
 }
 
-// NewWavefrontAdapter creates a new Wavefront adapter that listens at provided port.
-func NewWavefrontAdapter(addr string) (Server, error) {
+// Shutdown gracefully shuts down the server. It stops accepting requests,
+// waits for the requests in flight to complete and for all the reporters to
+// flush their metrics. If ctx is done first, the remaining requests are
+// cancelled, the flushes are abandoned and the context's error is returned.
+func (wa *WavefrontAdapter) Shutdown(ctx context.Context) error {
+	// the health endpoints keep serving, reporting the adapter as not ready
+	wa.stop()
+	if wa.health != nil {
+		defer wa.health.close()
+	}
+	if wa.metrics != nil {
+		defer wa.metrics.close()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		wa.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Warnf("requests didn't complete before the shutdown deadline, cancelling them")
+		wa.server.Stop()
+	}
+
+	if err := wa.reporters.shutdown(ctx); err != nil {
+		log.Warnf("reporters didn't flush before the shutdown deadline, metrics may be lost")
+		return err
+	}
+	log.Infof("all reporters were flushed")
+	return ctx.Err()
+}
+
+// WithTLS serves the adapter over TLS, optionally verifying client certificates.
+func WithTLS(options TLSOptions) Option {
+	return func(o *serverOptions) {
+		o.tls = &options
+	}
+}
+
+// WithConfig sets the configuration used for the requests that don't carry
+// any, which allows running the adapter standalone.
+func WithConfig(cfg *config.Params) Option {
+	return func(o *serverOptions) {
+		o.config = cfg
+	}
+}
+
+// WithHealthAddress serves the HTTP health endpoints at the given address.
+func WithHealthAddress(addr string) Option {
+	return func(o *serverOptions) {
+		o.healthAddr = addr
+	}
+}
+
+// WithMetricsAddress serves the metrics about the adapter itself in the
+// Prometheus text format at the given address.
+func WithMetricsAddress(addr string) Option {
+	return func(o *serverOptions) {
+		o.metricsAddr = addr
+	}
+}
+
+// validateReporterConfig validates the part of a configuration a reporter is
+// built from.
+func validateReporterConfig(cfg *config.Params) error {
+	if err := config.ValidateCredentials(cfg); err != nil {
+		return err
+	}
+	if err := config.ValidateFlushInterval(cfg); err != nil {
+		return err
+	}
+	return config.ValidateSpool(cfg)
+}
+
+// validateConfig validates a standalone adapter configuration.
+func validateConfig(cfg *config.Params) error {
+	if err := validateReporterConfig(cfg); err != nil {
+		return err
+	}
+	if _, err := config.ValidateTagRewrites(cfg); err != nil {
+		return err
+	}
+	return config.ValidateMetrics(cfg)
+}
+
+// NewWavefrontAdapter creates a new Wavefront adapter that listens at provided
+// port, or at provided host and port.
+func NewWavefrontAdapter(addr string, options ...Option) (Server, error) {
+	opts := &serverOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	if opts.config != nil {
+		if err := validateConfig(opts.config); err != nil {
+			return nil, fmt.Errorf("invalid adapter configuration: %v", err)
+		}
+	}
+
+	var grpcOptions []grpc.ServerOption
+	if opts.tls != nil {
+		certs, err := newCertStore(*opts.tls)
+		if err != nil {
+			return nil, fmt.Errorf("unable to configure TLS: %v", err)
+		}
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(certs.tlsConfig())))
+	}
+
 	if addr == "" {
 		addr = "0"
 	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = fmt.Sprintf(":%s", addr)
+	}
 
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", addr))
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("unable to listen on socket: %v", err)
 	}
 
 	adapter := &WavefrontAdapter{
-		listener: listener,
-		server:   grpc.NewServer(),
-		reporter: nil,
+		listener:   listener,
+		server:     grpc.NewServer(grpcOptions...),
+		reporters:  newReporterCache(),
+		config:     opts.config,
+		grpcHealth: health.NewServer(),
+		done:       make(chan struct{}),
+	}
+	if opts.healthAddr != "" {
+		if adapter.health, err = newHTTPServer("health", opts.healthAddr, newHealthHandler(adapter.ready)); err != nil {
+			_ = adapter.Close()
+			return nil, err
+		}
 	}
+	if opts.metricsAddr != "" {
+		if adapter.metrics, err = newHTTPServer("metrics", opts.metricsAddr, newMetricsHandler()); err != nil {
+			_ = adapter.Close()
+			return nil, err
+		}
+		// the system stats are exported even before any reporter is created
+		systemStatsOnce.Do(collectSystemStats)
+	}
+
+	// in standalone mode, the reporter is initialized right away so that
+	// readiness reflects whether the configuration works
+	if adapter.config != nil {
+		_, _ = adapter.verifyAndInitReporter(adapter.reporters.key(nil, adapter.config), adapter.config)
+	}
+
 	metric.RegisterHandleMetricServiceServer(adapter.server, adapter)
+	healthpb.RegisterHealthServer(adapter.server, adapter.grpcHealth)
+	adapter.updateHealth()
+	go adapter.watchHealth()
 	fmt.Printf("listening on \"%v\"\n", adapter.Addr())
 	return adapter, nil
 }
@@ -0,0 +1,608 @@
+// Copyright 2018 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefront
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/vmware/wavefront-adapter-for-istio/wavefront/config"
+	wf "github.com/wavefronthq/go-metrics-wavefront/reporting"
+	wfhistogram "github.com/wavefronthq/wavefront-sdk-go/histogram"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/metric"
+)
+
+func TestTranslateDuration(t *testing.T) {
+	duration := &policy.Duration{Value: types.DurationProto(1500 * time.Millisecond)}
+
+	table := []struct {
+		unit  config.Params_MetricInfo_DurationUnit
+		value float64
+	}{
+		{config.NANOSECONDS, 1500000000},
+		{config.MICROSECONDS, 1500000},
+		{config.MILLISECONDS, 1500},
+		{config.SECONDS, 1.5},
+	}
+
+	for _, entry := range table {
+		if value, err := translateDuration(duration, entry.unit); err != nil || value != entry.value {
+			t.Errorf("Translation failed for %v, got: %v (%v), want: %v.", entry.unit, value, err, entry.value)
+		}
+	}
+
+	if _, err := translateDuration(&policy.Duration{}, config.NANOSECONDS); err == nil {
+		t.Errorf("Expected an error for a duration without value.")
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	metric := &config.Params_MetricInfo{DurationUnit: config.MILLISECONDS}
+	duration := &policy.Duration{Value: types.DurationProto(2*time.Second + 250*time.Millisecond)}
+
+	table := []struct {
+		in  interface{}
+		out interface{}
+	}{
+		{int64(42), int64(42)},
+		{3.14, 3.14},
+		{"value", "value"},
+		{"42", "42"},
+		{true, true},
+		{duration, float64(2250)},
+	}
+
+	for _, entry := range table {
+		if out, err := convertValue(metric, entry.in); err != nil || out != entry.out {
+			t.Errorf("Conversion failed for %v, got: %v (%v), want: %v.", entry.in, out, err, entry.out)
+		}
+	}
+}
+
+func TestConvertValueOptions(t *testing.T) {
+	metric := &config.Params_MetricInfo{
+		ConvertBools: true,
+		ParseStrings: true,
+		ValueMap:     map[string]float64{"UP": 1, "DOWN": 0},
+	}
+
+	table := []struct {
+		in    interface{}
+		out   interface{}
+		valid bool
+	}{
+		{true, int64(1), true},
+		{false, int64(0), true},
+		{"UP", float64(1), true},
+		{"DOWN", float64(0), true},
+		{"42", int64(42), true},
+		{" -7 ", int64(-7), true},
+		{"3.14", 3.14, true},
+		{"1e3", float64(1000), true},
+		{"UNKNOWN", nil, false},
+		{int64(42), int64(42), true},
+	}
+
+	for _, entry := range table {
+		out, err := convertValue(metric, entry.in)
+		if (err == nil) != entry.valid || out != entry.out {
+			t.Errorf("Conversion failed for %v, got: %v (%v), want: %v.", entry.in, out, err, entry.out)
+		}
+	}
+
+	// mapped strings don't need parsing to be enabled
+	metric = &config.Params_MetricInfo{ValueMap: map[string]float64{"UP": 1}}
+	if out, err := convertValue(metric, "UP"); err != nil || out != float64(1) {
+		t.Errorf("Expected a mapped value, got: %v (%v).", out, err)
+	}
+}
+
+func TestDecodeTags(t *testing.T) {
+	rewriters, err := config.ValidateTagRewrites(&config.Params{TagRewrites: []*config.Params_TagRewrite{
+		{Tag: "request_path", Match: "/[0-9]+", Replacement: "/{id}"},
+		{Tag: "request_path", MaxLength: 12},
+		{Tag: "missing", Lowercase: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	formats := map[string]config.Params_TagFormat{"request_time": config.EPOCH_SECONDS}
+	dimensions := map[string]*policy.Value{
+		"request_path":  {Value: &policy.Value_StringValue{StringValue: "/users/12345/orders/67"}},
+		"response_code": {Value: &policy.Value_Int64Value{Int64Value: 200}},
+		"request_time":  {Value: &policy.Value_TimestampValue{TimestampValue: &policy.TimeStamp{Value: &types.Timestamp{Seconds: 1555429520}}}},
+		"response_time": {Value: &policy.Value_TimestampValue{TimestampValue: &policy.TimeStamp{Value: &types.Timestamp{Seconds: 1555429520}}}},
+	}
+
+	expected := map[string]string{
+		"request_path":  "/users/{id}/",
+		"response_code": "200",
+		"request_time":  "1555429520",
+		"response_time": "2019-04-16T15:45:20Z",
+	}
+	if tags := decodeTags(dimensions, formats, rewriters); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Decoding failed, got: %v, want: %v.", tags, expected)
+	}
+}
+
+func TestFormatTagValue(t *testing.T) {
+	timestamp, _ := types.TimestampProto(time.Date(2019, 4, 16, 15, 45, 20, 0, time.FixedZone("CEST", 2*3600)))
+
+	ipv4 := &policy.IPAddress{Value: []byte{10, 0, 0, 1}}
+	ipv6 := &policy.IPAddress{Value: []byte{0xfd, 0, 15: 1}}
+	duration := &policy.Duration{Value: types.DurationProto(1500 * time.Millisecond)}
+
+	table := []struct {
+		in     interface{}
+		format config.Params_TagFormat
+		out    string
+	}{
+		{"reviews", config.AUTO, "reviews"},
+		{int64(200), config.AUTO, "200"},
+		{true, config.AUTO, "true"},
+		{"reviews", config.EPOCH_SECONDS, "reviews"},
+		{ipv4, config.AUTO, "10.0.0.1"},
+		{ipv4, config.COLON_NOTATION, "::ffff:a00:1"},
+		{ipv6, config.AUTO, "fd00::1"},
+		{ipv6, config.COLON_NOTATION, "fd00::1"},
+		{&policy.IPAddress{Value: []byte{10, 0, 0, 1, 2}}, config.AUTO, "?0a00000102"},
+		{&policy.TimeStamp{Value: timestamp}, config.AUTO, "2019-04-16T13:45:20Z"},
+		{&policy.TimeStamp{Value: timestamp}, config.EPOCH_SECONDS, "1555422320"},
+		{&policy.TimeStamp{Value: timestamp}, config.EPOCH_MILLISECONDS, "1555422320000"},
+		{duration, config.AUTO, "1.5s"},
+		{duration, config.COLON_NOTATION, "1.5s"},
+		{&policy.DNSName{Value: "reviews.default.svc.cluster.local"}, config.AUTO, "reviews.default.svc.cluster.local"},
+		{&policy.EmailAddress{Value: "user@example.com"}, config.AUTO, "user@example.com"},
+		{&policy.Uri{Value: "spiffe://cluster.local/ns/default"}, config.AUTO, "spiffe://cluster.local/ns/default"},
+	}
+
+	for _, entry := range table {
+		if out := formatTagValue(entry.in, entry.format); out != entry.out {
+			t.Errorf("Formatting failed for %v in %v, got: %q, want: %q.", entry.in, entry.format, out, entry.out)
+		}
+	}
+}
+
+func TestWriteMetricsCounterGauge(t *testing.T) {
+	cfg := &config.Params{
+		Metrics: []*config.Params_MetricInfo{
+			{Name: "requestcount", InstanceName: "requestcount.instance.istio-system", Type: config.COUNTER},
+			{Name: "requestsize", InstanceName: "requestsize.instance.istio-system", Type: config.GAUGE},
+			{Name: "responsesize", InstanceName: "responsesize.instance.istio-system", Type: config.GAUGE, Aggregation: config.MAX},
+		},
+	}
+	reporter := &fakeReporter{registry: metrics.NewRegistry()}
+	hr := &handlerReporter{reporter: reporter, series: newSeriesLimiter()}
+
+	var insts []*metric.InstanceMsg
+	for _, v := range []int64{3, 7, 5} {
+		for _, name := range []string{"requestcount", "requestsize", "responsesize"} {
+			insts = append(insts, &metric.InstanceMsg{
+				Name:  name + ".instance.istio-system",
+				Value: &policy.Value{Value: &policy.Value_Int64Value{Int64Value: v}},
+			})
+		}
+	}
+	// counters take integers only, whatever their type
+	for _, v := range []float64{2, 2.5} {
+		insts = append(insts, &metric.InstanceMsg{
+			Name:  "requestcount.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_DoubleValue{DoubleValue: v}},
+		})
+	}
+	(&WavefrontAdapter{}).writeMetrics(hr, cfg, nil, insts, testHandlerStats(cfg))
+
+	if counter, ok := reporter.GetMetric("requestcount", map[string]string{}).(metrics.Counter); !ok || counter.Count() != 17 {
+		t.Errorf("Expected the counter to be the running total, got: %v.", counter)
+	}
+	if gauge, ok := reporter.GetMetric("requestsize", map[string]string{}).(metrics.GaugeFloat64); !ok || gauge.Value() != 5 {
+		t.Errorf("Expected the gauge to be the last value, got: %v.", gauge)
+	}
+	if gauge, ok := reporter.GetMetric("responsesize", map[string]string{}).(metrics.GaugeFloat64); !ok || gauge.Value() != 7 {
+		t.Errorf("Expected the gauge to be the maximum value, got: %v.", gauge)
+	}
+}
+
+func TestWriteMetricsTimerMeter(t *testing.T) {
+	cfg := &config.Params{
+		Metrics: []*config.Params_MetricInfo{
+			{Name: "requestduration", InstanceName: "requestduration.instance.istio-system", Type: config.TIMER, DurationUnit: config.MILLISECONDS},
+			{Name: "requestcount", InstanceName: "requestcount.instance.istio-system", Type: config.METER},
+		},
+	}
+	reporter := &fakeReporter{registry: metrics.NewRegistry()}
+	hr := &handlerReporter{reporter: reporter, series: newSeriesLimiter()}
+
+	var insts []*metric.InstanceMsg
+	for _, d := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond} {
+		insts = append(insts, &metric.InstanceMsg{
+			Name:  "requestduration.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_DurationValue{DurationValue: &policy.Duration{Value: types.DurationProto(d)}}},
+		}, &metric.InstanceMsg{
+			Name:  "requestcount.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 1}},
+		})
+	}
+	(&WavefrontAdapter{}).writeMetrics(hr, cfg, nil, insts, testHandlerStats(cfg))
+
+	timer, ok := reporter.GetMetric("requestduration", map[string]string{}).(metrics.Timer)
+	if !ok || timer.Count() != 2 || timer.Max() != 300 || timer.Mean() != 200 {
+		t.Errorf("Expected the durations to be timed in milliseconds, got: %v.", timer)
+	}
+	meter, ok := reporter.GetMetric("requestcount", map[string]string{}).(metrics.Meter)
+	if !ok || meter.Count() != 2 {
+		t.Errorf("Expected the requests to be metered, got: %v.", meter)
+	}
+}
+
+func TestWriteMetricsSubSecondDuration(t *testing.T) {
+	cfg := &config.Params{
+		Metrics: []*config.Params_MetricInfo{
+			{Name: "requestduration", InstanceName: "requestduration.instance.istio-system", Type: config.GAUGE,
+				DurationUnit: config.SECONDS, Aggregation: config.SUM},
+		},
+	}
+	if err := config.ValidateMetrics(cfg); err != nil {
+		t.Fatal(err)
+	}
+	reporter := &fakeReporter{registry: metrics.NewRegistry()}
+	hr := &handlerReporter{reporter: reporter, series: newSeriesLimiter()}
+
+	var insts []*metric.InstanceMsg
+	for _, d := range []time.Duration{300 * time.Millisecond, 1500 * time.Millisecond} {
+		insts = append(insts, &metric.InstanceMsg{
+			Name:  "requestduration.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_DurationValue{DurationValue: &policy.Duration{Value: types.DurationProto(d)}}},
+		})
+	}
+	(&WavefrontAdapter{}).writeMetrics(hr, cfg, nil, insts, testHandlerStats(cfg))
+
+	if gauge, ok := reporter.GetMetric("requestduration", map[string]string{}).(metrics.GaugeFloat64); !ok || gauge.Value() != 1.8 {
+		t.Errorf("Expected the durations to keep their fractional seconds, got: %v.", gauge)
+	}
+
+	// the other metric types would truncate them
+	cfg.Metrics[0].Type = config.TIMER
+	cfg.Metrics[0].Aggregation = config.LAST
+	if err := config.ValidateMetrics(cfg); err == nil {
+		t.Errorf("Expected durations in seconds to be refused for timers.")
+	}
+}
+
+// recordingHistogram is a wavefront histogram recording its values, which the
+// histogram itself only exposes once their time slice is over.
+type recordingHistogram struct {
+	wf.Histogram
+	values []int64
+}
+
+func (h *recordingHistogram) Update(v int64) {
+	h.values = append(h.values, v)
+	h.Histogram.Update(v)
+}
+
+func TestWriteMetricsWavefrontHistogram(t *testing.T) {
+	cfg := &config.Params{
+		Metrics: []*config.Params_MetricInfo{
+			{Name: "latency", InstanceName: "latency.instance.istio-system", Type: config.WAVEFRONT_HISTOGRAM},
+			{Name: "size", InstanceName: "size.instance.istio-system", Type: config.WAVEFRONT_HISTOGRAM, Granularity: config.HOUR},
+		},
+	}
+	reporter := &fakeReporter{registry: metrics.NewRegistry()}
+	hr := &handlerReporter{reporter: reporter, series: newSeriesLimiter()}
+
+	var insts []*metric.InstanceMsg
+	for _, v := range []int64{100, 300} {
+		insts = append(insts, &metric.InstanceMsg{
+			Name:  "latency.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_Int64Value{Int64Value: v}},
+		}, &metric.InstanceMsg{
+			Name:  "size.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_Int64Value{Int64Value: v}},
+		})
+	}
+	(&WavefrontAdapter{}).writeMetrics(hr, cfg, nil, insts, testHandlerStats(cfg))
+
+	table := []struct {
+		name        string
+		granularity wfhistogram.Granularity
+	}{
+		{"latency", wfhistogram.MINUTE},
+		{"size", wfhistogram.HOUR},
+	}
+	for _, entry := range table {
+		histogram, ok := reporter.GetMetric(entry.name, map[string]string{}).(wf.Histogram)
+		if !ok || histogram.Granularity() != entry.granularity {
+			t.Errorf("Expected a wavefront histogram by %v for %s, got: %v.", entry.granularity, entry.name, histogram)
+			continue
+		}
+
+		// the values recorded later go to the registered histogram
+		recording := &recordingHistogram{Histogram: histogram}
+		reporter.UnregisterMetric(entry.name, map[string]string{})
+		_ = reporter.RegisterMetric(entry.name, recording, map[string]string{})
+		(&WavefrontAdapter{}).writeMetrics(hr, cfg, nil, insts, testHandlerStats(cfg))
+		if want := []int64{100, 300}; !reflect.DeepEqual(recording.values, want) {
+			t.Errorf("Expected the values to be recorded by %s, got: %v, want: %v.", entry.name, recording.values, want)
+		}
+	}
+}
+
+func TestWriteMetricsTypeChange(t *testing.T) {
+	reporter := &fakeReporter{registry: metrics.NewRegistry()}
+	hr := &handlerReporter{reporter: reporter, series: newSeriesLimiter()}
+	insts := []*metric.InstanceMsg{{
+		Name:  "requests.instance.istio-system",
+		Value: &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 1}},
+	}}
+
+	// the same metric is given another type by each configuration change
+	table := []struct {
+		metricType config.Params_MetricInfo_Type
+		matches    func(interface{}) bool
+	}{
+		{config.COUNTER, isCounter},
+		{config.GAUGE, isGauge},
+		{config.METER, isMeter},
+		{config.TIMER, isTimer},
+		{config.HISTOGRAM, isHistogram},
+		{config.WAVEFRONT_HISTOGRAM, isWavefrontHistogram},
+		{config.COUNTER, isCounter},
+	}
+	sample := &config.Params_MetricInfo_Sample{Definition: &config.Params_MetricInfo_Sample_Uniform_{
+		Uniform: &config.Params_MetricInfo_Sample_Uniform{ReservoirSize: 100},
+	}}
+	for _, entry := range table {
+		cfg := &config.Params{Metrics: []*config.Params_MetricInfo{
+			{Name: "requests", InstanceName: "requests.instance.istio-system", Type: entry.metricType, Sample: sample},
+		}}
+		stats := testHandlerStats(cfg)
+		(&WavefrontAdapter{}).writeMetrics(hr, cfg, nil, insts, stats)
+
+		if m := reporter.GetMetric("requests", map[string]string{}); !entry.matches(m) {
+			t.Errorf("Expected the metric to be replaced with a %v, got: %T.", entry.metricType, m)
+		}
+		if dropped := stats.counter("instances.dropped", reasonTag, reasonTypeMismatch).Count(); dropped != 0 {
+			t.Errorf("Expected no instances to be dropped for %v, got: %d.", entry.metricType, dropped)
+		}
+	}
+}
+
+func TestVerifyAndInitReporter(t *testing.T) {
+	wa := &WavefrontAdapter{reporters: newReporterCache()}
+	defer wa.reporters.close()
+
+	table := []struct {
+		cfg      *config.Params
+		code     codes.Code
+		failures int
+	}{
+		// configuration errors aren't retried
+		{&config.Params{}, codes.InvalidArgument, 0},
+		{&config.Params{
+			Credentials:   &config.Params_Proxy{Proxy: &config.Params_WavefrontProxy{Address: "localhost:2878"}},
+			FlushInterval: -time.Second,
+		}, codes.InvalidArgument, 0},
+		// sender errors are
+		{&config.Params{
+			Credentials: &config.Params_Proxy{Proxy: &config.Params_WavefrontProxy{Address: "proxy.invalid:2878"}},
+		}, codes.Unavailable, 1},
+	}
+
+	for _, entry := range table {
+		_, err := wa.verifyAndInitReporter(reporterKey(entry.cfg), entry.cfg)
+		if code := status.Code(err); code != entry.code {
+			t.Errorf("Initialization failed for %v, got: %v, want: %v.", entry.cfg, err, entry.code)
+		}
+		wa.reporters.mu.Lock()
+		failures := len(wa.reporters.failures)
+		wa.reporters.mu.Unlock()
+		if failures != entry.failures {
+			t.Errorf("Expected %d failures to be retried for %v, got: %d.", entry.failures, entry.cfg, failures)
+		}
+	}
+}
+
+func TestNewWavefrontAdapter(t *testing.T) {
+	table := []struct {
+		addr    string
+		options []Option
+		prefix  string
+		valid   bool
+	}{
+		{"", nil, "", true},
+		{"0", nil, "", true},
+		{"127.0.0.1:0", nil, "127.0.0.1:", true},
+		{"127.0.0.1:0", []Option{WithConfig(&config.Params{})}, "", false},
+		{"127.0.0.1:0", []Option{WithConfig(&config.Params{
+			Credentials: &config.Params_Proxy{Proxy: &config.Params_WavefrontProxy{Address: "localhost:2878"}},
+		})}, "127.0.0.1:", true},
+	}
+
+	for _, entry := range table {
+		s, err := NewWavefrontAdapter(entry.addr, entry.options...)
+		if (err == nil) != entry.valid {
+			t.Errorf("Creation failed for %q, got: %v, want valid: %v.", entry.addr, err, entry.valid)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !strings.HasPrefix(s.Addr(), entry.prefix) {
+			t.Errorf("Listening failed for %q, got: %v, want prefix: %v.", entry.addr, s.Addr(), entry.prefix)
+		}
+		_ = s.Close()
+	}
+}
+
+func TestHealthServer(t *testing.T) {
+	var readiness error
+	hs, err := newHTTPServer("health", "127.0.0.1:0", newHealthHandler(func() error { return readiness }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go hs.run()
+	defer hs.close()
+
+	table := []struct {
+		path      string
+		readiness error
+		status    int
+		body      string
+	}{
+		{"/healthz", nil, http.StatusOK, "ok\n"},
+		{"/healthz", errors.New("not flushing"), http.StatusOK, "ok\n"},
+		{"/readyz", nil, http.StatusOK, "ok\n"},
+		{"/readyz", errors.New("not flushing"), http.StatusServiceUnavailable, "not flushing\n"},
+	}
+
+	for _, entry := range table {
+		readiness = entry.readiness
+		resp, err := http.Get("http://" + hs.listener.Addr().String() + entry.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != entry.status || string(body) != entry.body {
+			t.Errorf("Health check failed for %s, got: %d %q, want: %d %q.", entry.path, resp.StatusCode, body, entry.status, entry.body)
+		}
+	}
+}
+
+func TestGRPCHealth(t *testing.T) {
+	s, err := NewWavefrontAdapter("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	go s.Run(make(chan error, 1))
+
+	conn, err := grpc.Dial(s.Addr(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	check := func() healthpb.HealthCheckResponse_ServingStatus {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Status
+	}
+
+	if status := check(); status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected the adapter to be serving, got: %v.", status)
+	}
+	s.(*WavefrontAdapter).stop()
+	if status := check(); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected a stopping adapter not to be serving, got: %v.", status)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	// a fake Wavefront proxy collecting the received lines
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+	lines := make(chan string, 100)
+	go func() {
+		conn, err := proxy.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	cfg := &config.Params{
+		Credentials: &config.Params_Proxy{Proxy: &config.Params_WavefrontProxy{Address: proxy.Addr().String()}},
+		Source:      "istio",
+		Prefix:      "istio",
+		// long enough for nothing to be reported before the shutdown
+		FlushInterval: time.Hour,
+		Metrics: []*config.Params_MetricInfo{
+			{Name: "requestcount", InstanceName: "requestcount.instance.istio-system", Type: config.GAUGE},
+		},
+	}
+	s, err := NewWavefrontAdapter("127.0.0.1:0", WithConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Run(make(chan error, 1))
+
+	_, err = s.(*WavefrontAdapter).HandleMetric(context.Background(), &metric.HandleMetricRequest{
+		Instances: []*metric.InstanceMsg{{
+			Name:  "requestcount.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 42}},
+		}, {
+			Name:  "unknown.instance.istio-system",
+			Value: &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 1}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected a clean shutdown, got: %v.", err)
+	}
+
+	expected := map[string]bool{
+		"\"istio.requestcount.value\" 42 ":             true,
+		"\"istio.adapter.requests.count\" 1 ":          true,
+		"\"istio.adapter.instances.dropped.count\" 1 ": true,
+	}
+	timeout := time.After(5 * time.Second)
+	for len(expected) > 0 {
+		select {
+		case line := <-lines:
+			for prefix := range expected {
+				if strings.HasPrefix(line, prefix) {
+					delete(expected, prefix)
+				}
+			}
+		case <-timeout:
+			t.Fatalf("Expected the metrics to be flushed on shutdown, missing: %v.", expected)
+		}
+	}
+}